@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// alpineAdvisory is a fix from the Alpine secdb, vuln-list stores it at
+// alpine/<release>/<repository>/<package>/<CVE ID>.json.
+type alpineAdvisory struct {
+	VulnerabilityID string
+	Release         string
+	Package         string
+	Repository      string
+	FixedVersion    string
+}
+
+// alpineAdapter reads the Alpine secdb, which lists the version every release
+// fixed a CVE in but rates no severity.
+type alpineAdapter struct {
+	index *vendorIndex
+}
+
+func init() {
+	registerVendorAdapter("alpine", newAlpineAdapter)
+}
+
+func newAlpineAdapter(dir string) VendorAdapter {
+	return alpineAdapter{index: &vendorIndex{dir: dir, build: buildAlpineIndex}}
+}
+
+func buildAlpineIndex(dir string, _ func(file string, err error)) (map[string][]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*", "*", "CVE-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string][]string)
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), ".json")
+		index[id] = append(index[id], file)
+	}
+	return index, nil
+}
+
+func (alpineAdapter) Name() string { return "alpine" }
+
+func (v alpineAdapter) Locate(cveID, _ string) ([]string, error) { return v.index.Files(cveID) }
+
+func (v alpineAdapter) CVEs(year string) ([]string, error) { return v.index.CVEs(year) }
+
+func (v alpineAdapter) Load(cveID, _ string) (VendorInfo, error) {
+	files, err := v.index.Files(cveID)
+	if err != nil {
+		return VendorInfo{}, err
+	}
+	info := VendorInfo{Name: v.Name()}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return VendorInfo{}, err
+		}
+		var a alpineAdvisory
+		if err := json.Unmarshal(b, &a); err != nil {
+			return VendorInfo{}, fmt.Errorf("%s: %w", file, err)
+		}
+		// the secdb lists CVEs that never affected a package as fixed in 0
+		if a.FixedVersion == "" || a.FixedVersion == "0" {
+			continue
+		}
+		info.AffectedSoftware = append(info.AffectedSoftware, fixedIn("Alpine", a.Release, a.Package, a.FixedVersion, a.Repository))
+	}
+	if len(info.AffectedSoftware) == 0 {
+		return VendorInfo{}, os.ErrNotExist
+	}
+	return info, nil
+}
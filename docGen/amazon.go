@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// amazonAdvisory is an Amazon Linux Security Advisory (ALAS), vuln-list stores
+// it at amazon/<Amazon Linux version>/<ALAS ID>.json.
+type amazonAdvisory struct {
+	ID       string   `json:"id"`
+	Severity string   `json:"severity"`
+	CVEIDs   []string `json:"cveids"`
+	Packages []struct {
+		Name    string `json:"name"`
+		Epoch   string `json:"epoch"`
+		Version string `json:"version"`
+		Release string `json:"release"`
+	} `json:"packages"`
+}
+
+// amazonSeverities orders the severities of ALAS, lowest first.
+var amazonSeverities = []string{"low", "medium", "important", "critical"}
+
+// amazonAdapter reads the ALAS of every Amazon Linux version.
+type amazonAdapter struct {
+	index *vendorIndex
+}
+
+func init() {
+	registerVendorAdapter("amazon", newAmazonAdapter)
+}
+
+func newAmazonAdapter(dir string) VendorAdapter {
+	return amazonAdapter{index: &vendorIndex{dir: dir, build: buildAmazonIndex}}
+}
+
+func buildAmazonIndex(dir string, skip func(file string, err error)) (map[string][]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string][]string)
+	for _, file := range files {
+		a, err := readAmazonAdvisory(file)
+		if err != nil {
+			skip(file, err)
+			continue
+		}
+		for _, id := range a.CVEIDs {
+			index[id] = append(index[id], file)
+		}
+	}
+	return index, nil
+}
+
+func readAmazonAdvisory(file string) (amazonAdvisory, error) {
+	var a amazonAdvisory
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return a, err
+	}
+	if err := json.Unmarshal(b, &a); err != nil {
+		return a, fmt.Errorf("%s: %w", file, err)
+	}
+	return a, nil
+}
+
+func (amazonAdapter) Name() string { return "amazon" }
+
+func (v amazonAdapter) Locate(cveID, _ string) ([]string, error) { return v.index.Files(cveID) }
+
+func (v amazonAdapter) CVEs(year string) ([]string, error) { return v.index.CVEs(year) }
+
+func (v amazonAdapter) Skipped() map[string]error { return v.index.Skipped() }
+
+func (v amazonAdapter) Load(cveID, _ string) (VendorInfo, error) {
+	files, err := v.index.Files(cveID)
+	if err != nil {
+		return VendorInfo{}, err
+	}
+	if len(files) == 0 {
+		return VendorInfo{}, os.ErrNotExist
+	}
+
+	info := VendorInfo{Name: v.Name()}
+	var severities []string
+	for _, file := range files {
+		a, err := readAmazonAdvisory(file)
+		if err != nil {
+			return VendorInfo{}, err
+		}
+		severities = append(severities, a.Severity)
+
+		release := filepath.Base(filepath.Dir(file))
+		for _, p := range a.Packages {
+			version := fmt.Sprintf("%s-%s", p.Version, p.Release)
+			if p.Epoch != "" && p.Epoch != "0" {
+				version = p.Epoch + ":" + version
+			}
+			// advisories list a package once per architecture
+			as := fixedIn("Amazon Linux", release, p.Name, version, a.ID)
+			if !slices.Contains(info.AffectedSoftware, as) {
+				info.AffectedSoftware = append(info.AffectedSoftware, as)
+			}
+		}
+	}
+	info.Severity = severityOf(severities, amazonSeverities)
+	return info, nil
+}
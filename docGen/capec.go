@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/leekchan/gtf"
+
+	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
+)
+
+// capecCatalog is the CAPEC catalog published at
+// https://capec.mitre.org/data/xml/capec_latest.xml. A JSON export is read the
+// same way, with the element and attribute names of the XML as keys.
+type capecCatalog struct {
+	AttackPatterns struct {
+		AttackPattern []AttackPattern `xml:"Attack_Pattern" json:"Attack_Pattern"`
+	} `xml:"Attack_Patterns" json:"Attack_Patterns"`
+}
+
+// AttackPattern is an attack pattern of the CAPEC catalog.
+type AttackPattern struct {
+	ID          int    `xml:"ID,attr"`
+	Name        string `xml:"Name,attr"`
+	Status      string `xml:"Status,attr"`
+	Description string
+	Likelihood  string `xml:"Likelihood_Of_Attack" json:"Likelihood_Of_Attack"`
+	Severity    string `xml:"Typical_Severity" json:"Typical_Severity"`
+	// Prerequisites are the conditions the attack requires to succeed.
+	Prerequisites struct {
+		Prerequisite []string
+	}
+	// RelatedWeaknesses are the CWEs the attack exploits.
+	RelatedWeaknesses struct {
+		RelatedWeakness []struct {
+			CWEID int `xml:"CWE_ID,attr" json:"CWE_ID"`
+		} `xml:"Related_Weakness" json:"Related_Weakness"`
+	} `xml:"Related_Weaknesses" json:"Related_Weaknesses"`
+}
+
+// loadCAPECCatalog reads the CAPEC catalog at path, as XML when it has the .xml
+// extension and as JSON otherwise, and indexes it by ID. Deprecated attack
+// patterns are left out.
+func loadCAPECCatalog(path string) (map[int]AttackPattern, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog capecCatalog
+	if strings.EqualFold(filepath.Ext(path), ".xml") {
+		err = xml.Unmarshal(b, &catalog)
+	} else {
+		err = json.Unmarshal(b, &catalog)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	capec := make(map[int]AttackPattern, len(catalog.AttackPatterns.AttackPattern))
+	for _, p := range catalog.AttackPatterns.AttackPattern {
+		if p.ID == 0 || p.Status == "Deprecated" {
+			continue
+		}
+		// the XML is indented, so text spans lines
+		p.Description = strings.Join(strings.Fields(p.Description), " ")
+		for i, prerequisite := range p.Prerequisites.Prerequisite {
+			p.Prerequisites.Prerequisite[i] = strings.Join(strings.Fields(prerequisite), " ")
+		}
+		capec[p.ID] = p
+	}
+	return capec, nil
+}
+
+// loadCAPECInput loads the catalog of the capec input of a source, its pages are
+// rendered without it when there is no such input or file.
+func loadCAPECInput(ctx *source.Context) map[int]AttackPattern {
+	path := ctx.Input("capec")
+	if path == "" {
+		return nil
+	}
+	capec, err := loadCAPECCatalog(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		log.Printf("no CAPEC catalog at %s, attack patterns link to capec.mitre.org", path)
+	case err != nil:
+		ctx.RecordError(path, report.StageLoad, err)
+	}
+	return capec
+}
+
+// resolveAttackPatterns sets the related attack patterns of a weakness that are
+// in the CAPEC catalog to their entry.
+func (w *WeaknessType) resolveAttackPatterns(capec map[int]AttackPattern) {
+	for i, r := range w.RelatedAttackPatterns.RelatedAttackPattern {
+		if p, ok := capec[r.CAPECID]; ok {
+			w.RelatedAttackPatterns.RelatedAttackPattern[i].Pattern = &p
+		}
+	}
+}
+
+// Markdown lists the attack pattern, with its likelihood, severity and
+// prerequisites when it was resolved from the CAPEC catalog.
+func (r RelatedAttackPattern) Markdown() string {
+	p := r.Pattern
+	if p == nil {
+		return fmt.Sprintf("- [CAPEC-%d](https://capec.mitre.org/data/definitions/%d.html)", r.CAPECID, r.CAPECID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "- [CAPEC-%d: %s](/capec/capec-%d)", p.ID, p.Name, p.ID)
+	if p.Likelihood != "" {
+		fmt.Fprintf(&b, "\n  - Likelihood: %s", p.Likelihood)
+	}
+	if p.Severity != "" {
+		fmt.Fprintf(&b, "\n  - Severity: %s", p.Severity)
+	}
+	if len(p.Prerequisites.Prerequisite) > 0 {
+		fmt.Fprintf(&b, "\n  - Prerequisites: %s", strings.Join(p.Prerequisites.Prerequisite, " "))
+	}
+	return b.String()
+}
+
+func init() {
+	source.Register("capec", func() source.Source { return capecSource{} })
+}
+
+// capecSource renders a page per attack pattern of the CAPEC catalog that
+// lists the CWEs it exploits and the CVEs referencing them.
+type capecSource struct{}
+
+// CAPECPage is an attack pattern, the weaknesses it exploits and their CVEs.
+type CAPECPage struct {
+	Pattern    AttackPattern
+	Weaknesses []Weakness
+	CVEs       []CWEReference
+}
+
+func (capecSource) Load(ctx *source.Context) ([]source.Document, error) {
+	path := ctx.Input("capec")
+	capec, err := loadCAPECCatalog(path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("no CAPEC catalog at %s, skipping attack pattern pages", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cweDocs, err := loadWeaknesses(ctx, ctx.Input("cwe"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	cves, err := sharedCVEsByCWE(ctx, ctx.Input("nvd"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to index the CVEs of CWEs: %w", err)
+	}
+
+	// a pattern relates to the CWEs it lists and to those that list it
+	weaknesses := make(map[int]WeaknessType)
+	related := make(map[int][]int)
+	for _, p := range capec {
+		for _, r := range p.RelatedWeaknesses.RelatedWeakness {
+			if !slices.Contains(related[p.ID], r.CWEID) {
+				related[p.ID] = append(related[p.ID], r.CWEID)
+			}
+		}
+	}
+	for _, doc := range cweDocs {
+		w := doc.Data.(WeaknessType)
+		weaknesses[w.ID] = w
+		for _, r := range w.RelatedAttackPatterns.RelatedAttackPattern {
+			if _, ok := capec[r.CAPECID]; ok && !slices.Contains(related[r.CAPECID], w.ID) {
+				related[r.CAPECID] = append(related[r.CAPECID], w.ID)
+			}
+		}
+	}
+
+	var docs []source.Document
+	for _, p := range capec {
+		page := CAPECPage{Pattern: p}
+		cweIDs := related[p.ID]
+		sort.Ints(cweIDs)
+		for _, cweID := range cweIDs {
+			id := fmt.Sprintf("CWE-%d", cweID)
+			page.Weaknesses = append(page.Weaknesses, Weakness{ID: id, Info: weaknesses[cweID]})
+			for _, c := range cves[id] {
+				if !slices.Contains(page.CVEs, c) {
+					page.CVEs = append(page.CVEs, c)
+				}
+			}
+		}
+		sortCWEReferences(page.CVEs)
+		docs = append(docs, source.Document{ID: fmt.Sprintf("CAPEC-%d", p.ID), Path: path, Data: page})
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].Data.(CAPECPage).Pattern.ID < docs[j].Data.(CAPECPage).Pattern.ID
+	})
+	return docs, nil
+}
+
+func (capecSource) Render(ctx *source.Context, docs []source.Document) error {
+	// there is no catalog to list
+	if len(docs) == 0 {
+		return nil
+	}
+	postsDir := ctx.Output()
+	log.Printf("generating CAPEC pages in: %s", postsDir)
+	if err := os.MkdirAll(postsDir, 0755); err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		pageFile := filepath.Join(postsDir, fmt.Sprintf("%s.md", doc.ID))
+		var page bytes.Buffer
+		if err := CAPECPostToMarkdown(doc.Data.(CAPECPage), &page, GetCustomContentFromMarkdown(pageFile)); err != nil {
+			ctx.RecordError(doc.Path, report.StageRender, fmt.Errorf("%s: %w", doc.ID, err))
+			continue
+		}
+		if _, err := writeIfChanged(pageFile, page.Bytes()); err != nil {
+			ctx.RecordError(doc.Path, report.StageWrite, fmt.Errorf("%s: %w", doc.ID, err))
+		}
+	}
+
+	return menu.NewTopLevelMenu("CAPEC", "avd_list", filepath.Join(postsDir, "_index.md")).
+		WithHeading("Attack Patterns").
+		WithIcon("aqua").
+		WithCategory("vulnerabilities").
+		WithMenu("none").
+		Generate()
+}
+
+func CAPECPostToMarkdown(p CAPECPage, w io.Writer, customContent string) error {
+	t := template.Must(template.New("capecPost").Funcs(gtf.GtfTextFuncMap).Parse(capecPostTemplate))
+	if err := t.Execute(w, p); err != nil {
+		return err
+	}
+	if customContent != "" {
+		_, _ = io.WriteString(w, "\n"+customContent)
+	}
+	return nil
+}
+
+const capecPostTemplate = `---
+title: "CAPEC-{{.Pattern.ID}}"
+
+shortName: {{printf "%q" .Pattern.Name}}
+category: vulnerabilities
+draft: false
+
+avd_page_type: capec_page
+
+header_subtitle: {{printf "%q" .Pattern.Name}}
+
+sidebar_additional_info_capec: "https://capec.mitre.org/data/definitions/{{.Pattern.ID}}.html"
+
+breadcrumbs:
+  - name: CAPEC
+    path: /capec
+
+---
+
+{{.Pattern.Description}}
+
+{{- if or .Pattern.Likelihood .Pattern.Severity}}
+### Likelihood and Severity
+| Likelihood | Severity |
+| ------------- |-------------|
+| {{.Pattern.Likelihood | default "Unknown"}} | {{.Pattern.Severity | default "Unknown"}} |
+{{end}}
+
+{{- with .Pattern.Prerequisites.Prerequisite}}
+### Prerequisites{{range $p := .}}
+- {{$p}}{{end}}
+{{end}}
+
+{{- if .Weaknesses}}
+### Related Weaknesses {.with_icon .weakness}{{range $w := .Weaknesses}}
+- [{{$w.Title}}]({{$w.Link}}){{end}}
+{{end}}
+### Vulnerabilities
+{{- if .CVEs}}
+| CVE | Published |
+| ------------- |-------------|{{range $c := .CVEs}}
+| [{{$c.CVEID}}](/nvd/{{lower $c.CVEID}}) | {{$c.Published}} |{{end}}
+{{- else}}
+No CVE references the weaknesses of this attack pattern.
+{{- end}}
+
+<!--- Add Aqua content below --->`
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/avd-generator/source"
+)
+
+func TestLoadCAPECCatalog(t *testing.T) {
+	capec, err := loadCAPECCatalog("../goldens/capec/capec.xml")
+	require.NoError(t, err)
+	// deprecated patterns are left out
+	assert.Len(t, capec, 3)
+	assert.NotContains(t, capec, 215)
+
+	p := capec[7]
+	assert.Equal(t, "Blind SQL Injection", p.Name)
+	assert.Equal(t, "High", p.Likelihood)
+	assert.Equal(t, "High", p.Severity)
+	assert.Equal(t, []string{
+		"SQL queries used by the application to store, retrieve or modify data.",
+		"User-controllable input that is not properly validated by the application as part of SQL queries.",
+	}, p.Prerequisites.Prerequisite)
+	require.Len(t, p.RelatedWeaknesses.RelatedWeakness, 2)
+	assert.Equal(t, 89, p.RelatedWeaknesses.RelatedWeakness[0].CWEID)
+
+	fromJSON, err := loadCAPECCatalog("../goldens/capec/capec.json")
+	require.NoError(t, err)
+	assert.Len(t, fromJSON, 1)
+	assert.Equal(t, p, fromJSON[7])
+
+	_, err = loadCAPECCatalog("../goldens/capec/missing.xml")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRelatedAttackPatternMarkdown(t *testing.T) {
+	capec, err := loadCAPECCatalog("../goldens/capec/capec.xml")
+	require.NoError(t, err)
+
+	w := WeaknessType{RelatedAttackPatterns: RelatedAttackPatternsType{RelatedAttackPattern: []RelatedAttackPattern{{CAPECID: 463}, {CAPECID: 215}}}}
+	w.resolveAttackPatterns(capec)
+	assert.Equal(t, `- [CAPEC-463: Padding Oracle Crypto Attack](/capec/capec-463)
+  - Severity: High`, w.RelatedAttackPatterns.RelatedAttackPattern[0].Markdown())
+	// patterns missing from the catalog link to capec.mitre.org
+	assert.Equal(t, "- [CAPEC-215](https://capec.mitre.org/data/definitions/215.html)", w.RelatedAttackPatterns.RelatedAttackPattern[1].Markdown())
+}
+
+func TestGenerateCAPECPages(t *testing.T) {
+	nvdDir := t.TempDir()
+	b, err := os.ReadFile("../goldens/json/nvd/CVE-2020-0002.json")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(nvdDir, "CVE-2020-0002.json"), b, 0600))
+
+	pagesDir := t.TempDir()
+	inputs := map[string]string{"capec": "../goldens/capec/capec.xml", "cwe": "../goldens/cwe", "nvd": nvdDir}
+	result := source.Run(newTestContext(inputs, pagesDir, nil), capecSource{})
+	require.NoError(t, result.Err)
+	assert.Equal(t, 3, result.Documents)
+
+	got, err := os.ReadFile(filepath.Join(pagesDir, "CAPEC-7.md"))
+	require.NoError(t, err)
+	// CAPEC-7 doesn't list CWE-416, which lists CAPEC-7
+	assert.Equal(t, `---
+title: "CAPEC-7"
+
+shortName: "Blind SQL Injection"
+category: vulnerabilities
+draft: false
+
+avd_page_type: capec_page
+
+header_subtitle: "Blind SQL Injection"
+
+sidebar_additional_info_capec: "https://capec.mitre.org/data/definitions/7.html"
+
+breadcrumbs:
+  - name: CAPEC
+    path: /capec
+
+---
+
+Blind SQL Injection results from an insufficient mitigation for SQL Injection. Although suppressing database error messages are considered best practice, the suppression alone is not sufficient to prevent SQL Injection.
+### Likelihood and Severity
+| Likelihood | Severity |
+| ------------- |-------------|
+| High | High |
+
+### Prerequisites
+- SQL queries used by the application to store, retrieve or modify data.
+- User-controllable input that is not properly validated by the application as part of SQL queries.
+
+### Related Weaknesses {.with_icon .weakness}
+- [CWE-89](https://cwe.mitre.org/data/definitions/89.html)
+- [CWE-209](https://cwe.mitre.org/data/definitions/209.html)
+- [CWE-416: Generation of Error Message Containing Sensitive Information](/cwe/cwe-416)
+
+### Vulnerabilities
+| CVE | Published |
+| ------------- |-------------|
+| [CVE-2020-0002](/nvd/cve-2020-0002) | 2020-01-08 |
+
+<!--- Add Aqua content below --->`, string(got))
+
+	got, err = os.ReadFile(filepath.Join(pagesDir, "CAPEC-463.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(got), `
+### Related Weaknesses {.with_icon .weakness}
+- [CWE-209](https://cwe.mitre.org/data/definitions/209.html)
+- [CWE-416: Generation of Error Message Containing Sensitive Information](/cwe/cwe-416)
+`)
+
+	index, err := os.ReadFile(filepath.Join(pagesDir, "_index.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "avd_page_type: avd_list")
+}
+
+func TestGenerateCAPECPagesWithoutCatalog(t *testing.T) {
+	pagesDir := t.TempDir()
+	inputs := map[string]string{"capec": "../goldens/capec/missing.xml", "cwe": "../goldens/cwe", "nvd": t.TempDir()}
+	result := source.Run(newTestContext(inputs, pagesDir, nil), capecSource{})
+	require.NoError(t, result.Err)
+	assert.Equal(t, 0, result.Documents)
+
+	entries, err := os.ReadDir(pagesDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/Masterminds/semver"
 	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/source"
 	"gopkg.in/yaml.v3"
 )
 
@@ -35,11 +36,16 @@ type ChainBenchSectionsConfig struct {
 	Sections map[string]ChainBenchRulesConfig
 }
 
-func generateChainBenchPages(configDir, outputDir string) {
-	var rulesConfigs []ChainBenchRulesConfig
-	var sectionsConfigs []ChainBenchSectionsConfig
+func init() {
+	source.Register("chain-bench", func() source.Source { return chainBenchSource{} })
+}
+
+type chainBenchSource struct{}
 
-	if err := filepath.Walk(configDir, func(path string, info fs.FileInfo, err error) error {
+func (chainBenchSource) Load(ctx *source.Context) ([]source.Document, error) {
+	var docs []source.Document
+
+	if err := filepath.Walk(ctx.Input("checks"), func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -58,19 +64,34 @@ func generateChainBenchPages(configDir, outputDir string) {
 			if err := yaml.Unmarshal(content, &ruleConfig); err != nil {
 				return err
 			}
-			rulesConfigs = append(rulesConfigs, ruleConfig)
+			docs = append(docs, source.Document{ID: ruleConfig.ID, Path: path, Data: ruleConfig})
 		case "sections.metadata.json":
 			var sectionConfig ChainBenchSectionsConfig
 			if err := yaml.Unmarshal(content, &sectionConfig); err != nil {
 				return err
 			}
-			sectionsConfigs = append(sectionsConfigs, sectionConfig)
+			docs = append(docs, source.Document{ID: sectionConfig.ID, Path: path, Data: sectionConfig})
 		}
 
 		return nil
 	}); err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
+	return docs, nil
+}
+
+func (chainBenchSource) Render(ctx *source.Context, docs []source.Document) error {
+	var rulesConfigs []ChainBenchRulesConfig
+	var sectionsConfigs []ChainBenchSectionsConfig
+	for _, doc := range docs {
+		switch config := doc.Data.(type) {
+		case ChainBenchRulesConfig:
+			rulesConfigs = append(rulesConfigs, config)
+		case ChainBenchSectionsConfig:
+			sectionsConfigs = append(sectionsConfigs, config)
+		}
+	}
+
 	versionedConf := make(map[string]ChainBenchSectionsConfig, 3)
 
 	for _, sectionConfig := range sectionsConfigs {
@@ -86,12 +107,10 @@ func generateChainBenchPages(configDir, outputDir string) {
 
 	versioned := make(map[string]map[string]ChainBenchSectionsConfig)
 	versioned["cis-1.0"] = versionedConf
-	if err := writeSupplyChainTemplates(versioned, outputDir); err != nil {
-		fmt.Println(err)
-	}
+	return writeSupplyChainTemplates(versioned, ctx.Output(), ctx.Menu)
 }
 
-func writeSupplyChainTemplates(versionedConfigs map[string]map[string]ChainBenchSectionsConfig, outputDir string) error {
+func writeSupplyChainTemplates(versionedConfigs map[string]map[string]ChainBenchSectionsConfig, outputDir string, complianceMenu *menu.Menu) error {
 	complianceMenu.AddNode("softwaresupplychain", "Software Supply Chain", outputDir, "compliance", []string{},
 		[]menu.BreadCrumb{{Name: "Compliance", Url: "/compliance"}}, "chainbench", true)
 
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -12,7 +14,8 @@ import (
 func TestChainBenchPages(t *testing.T) {
 
 	pagesDir := t.TempDir()
-	generateChainBenchPages("../goldens/chain-bench/originals", pagesDir)
+	result := source.Run(newTestContext(map[string]string{"checks": "../goldens/chain-bench/originals"}, pagesDir, menu.New("compliance", pagesDir)), chainBenchSource{})
+	require.NoError(t, result.Err)
 	gotBytes, err := ioutil.ReadFile(filepath.Join(pagesDir, "softwaresupplychain", "cis-1.0", "cis-1.0-buildpipelines", "2.3.md"))
 	require.NoError(t, err)
 
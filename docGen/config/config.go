@@ -0,0 +1,166 @@
+package config
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed default.yaml
+var defaultConfig []byte
+
+// Config describes which sources the generator reads, where their pages are
+// written and which menus they contribute to.
+type Config struct {
+	// ContentDir is the root every source output and menu directory is relative to.
+	ContentDir string `yaml:"content"`
+	// Repos maps a checkout name to its location on disk. An input path whose first
+	// element is a repo name is resolved against that location.
+	Repos   map[string]string `yaml:"repos"`
+	Menus   []Menu            `yaml:"menus"`
+	Sources []Source          `yaml:"sources"`
+	// Report is where the JSON run report is written, none is written when empty.
+	Report     string      `yaml:"report"`
+	Thresholds []Threshold `yaml:"thresholds"`
+}
+
+// Menu is a top level section of the site, such as Misconfiguration or Compliance.
+type Menu struct {
+	Name     string `yaml:"name"`
+	Dir      string `yaml:"dir"`
+	Title    string `yaml:"title"`
+	Heading  string `yaml:"heading"`
+	Icon     string `yaml:"icon"`
+	Category string `yaml:"category"`
+}
+
+// Threshold fails the run when a source records more than Max failures in a
+// stage, such as parse. An empty stage counts the failures of every stage.
+type Threshold struct {
+	Source string `yaml:"source"`
+	Stage  string `yaml:"stage"`
+	Max    int    `yaml:"max"`
+}
+
+type Source struct {
+	Name    string            `yaml:"name"`
+	Type    string            `yaml:"type"`
+	Inputs  map[string]string `yaml:"inputs"`
+	Output  string            `yaml:"output"`
+	Menu    string            `yaml:"menu"`
+	Options map[string]string `yaml:"options"`
+}
+
+// Default returns the configuration matching the layout created by the
+// Makefile's md-clone-all target.
+func Default() Config {
+	c, err := parse(defaultConfig)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default config: %s", err))
+	}
+	return c
+}
+
+func Load(path string) (Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	c, err := parse(b)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+func parse(b []byte) (Config, error) {
+	var c Config
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return Config{}, err
+	}
+	if c.ContentDir == "" {
+		c.ContentDir = "content"
+	}
+	return c, c.validate()
+}
+
+func (c Config) validate() error {
+	names := make(map[string]bool)
+	for _, s := range c.Sources {
+		if s.Name == "" || s.Type == "" {
+			return fmt.Errorf("source %q must have a name and a type", s.Name)
+		}
+		if names[s.Name] {
+			return fmt.Errorf("source %q is declared more than once", s.Name)
+		}
+		names[s.Name] = true
+		if s.Menu != "" {
+			if _, ok := c.Menu(s.Menu); !ok {
+				return fmt.Errorf("source %q refers to unknown menu %q", s.Name, s.Menu)
+			}
+		}
+	}
+	for _, t := range c.Thresholds {
+		if !names[t.Source] {
+			return fmt.Errorf("threshold refers to unknown source %q", t.Source)
+		}
+	}
+	return nil
+}
+
+func (c Config) Source(name string) (Source, bool) {
+	for _, s := range c.Sources {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Source{}, false
+}
+
+func (c Config) Menu(name string) (Menu, bool) {
+	for _, m := range c.Menus {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Menu{}, false
+}
+
+// Input returns the named input directory of a source, resolved against the repos.
+func (c Config) Input(s Source, name string) string {
+	return c.resolve(s.Inputs[name])
+}
+
+// Output returns the directory a source writes its pages to.
+func (c Config) Output(s Source) string {
+	return filepath.Join(c.ContentDir, s.Output)
+}
+
+// MenuDir returns the directory the top level page of a menu is written to.
+func (c Config) MenuDir(m Menu) string {
+	return filepath.Join(c.ContentDir, m.Dir)
+}
+
+// ReposOf returns the names of the repos a source reads from.
+func (c Config) ReposOf(s Source) []string {
+	var repos []string
+	for _, input := range s.Inputs {
+		repo, _, _ := strings.Cut(filepath.ToSlash(input), "/")
+		if _, ok := c.Repos[repo]; ok {
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
+
+func (c Config) resolve(path string) string {
+	repo, rest, _ := strings.Cut(filepath.ToSlash(path), "/")
+	if root, ok := c.Repos[repo]; ok {
+		return filepath.Join(root, filepath.FromSlash(rest))
+	}
+	return path
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "generator.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+repos:
+  kube-hunter-repo: /src/kube-hunter
+menus:
+  - name: misconfig
+    dir: misconfig
+sources:
+  - name: kube-hunter
+    type: kube-hunter
+    inputs:
+      docs: kube-hunter-repo/docs/_kb
+      other: some/local/dir
+    output: misconfig/kubernetes
+    menu: misconfig
+`), 0600))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	src, ok := cfg.Source("kube-hunter")
+	require.True(t, ok)
+	assert.Equal(t, "/src/kube-hunter/docs/_kb", cfg.Input(src, "docs"))
+	assert.Equal(t, "some/local/dir", cfg.Input(src, "other"))
+	assert.Equal(t, "content/misconfig/kubernetes", cfg.Output(src))
+	assert.Equal(t, []string{"kube-hunter-repo"}, cfg.ReposOf(src))
+}
+
+func TestLoadUnknownMenu(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "generator.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+sources:
+  - name: tracee
+    type: tracee
+    menu: runsec
+`), 0600))
+
+	_, err := Load(path)
+	assert.ErrorContains(t, err, `source "tracee" refers to unknown menu "runsec"`)
+}
+
+func TestDefault(t *testing.T) {
+	cfg := Default()
+	for _, src := range cfg.Sources {
+		for name := range src.Inputs {
+			assert.NotEmpty(t, cfg.Input(src, name), src.Name)
+		}
+	}
+}
@@ -12,24 +12,56 @@ import (
 	"text/template"
 
 	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/aquasecurity/avd-generator/util"
 )
 
-func generateCloudSploitPages(inputPagesDir, outputPagesDir, remediationsDir string) {
-	log.Printf("generating cloudsploit pages in: %s...", outputPagesDir)
-	var fileList []string
-	if err := filepath.Walk(inputPagesDir, func(path string, info os.FileInfo, err error) error {
+func init() {
+	source.Register("cloudsploit", func() source.Source { return cloudSploitSource{} })
+}
+
+type cloudSploitSource struct{}
+
+func (cloudSploitSource) Load(ctx *source.Context) ([]source.Document, error) {
+	var docs []source.Document
+	if err := filepath.Walk(ctx.Input("plugins"), func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		if info.IsDir() {
 			return nil
 		}
-		fileList = append(fileList, path)
+
+		if strings.HasSuffix(path, ".spec.js") {
+			// not interested in spec files
+			return nil
+		}
+
+		id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if slices.Contains(cloudsploitIgnores, id) {
+			fmt.Printf("Skipping '%s' because it is in the cloudsploit ignore list: \n", id)
+			return nil
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			ctx.RecordError(path, report.StageRead, err)
+			return nil
+		}
+
+		docs = append(docs, source.Document{ID: id, Path: path, Data: string(b)})
 		return nil
 	}); err != nil {
-		fail(err)
+		return nil, err
 	}
+	return docs, nil
+}
+
+func (cloudSploitSource) Render(ctx *source.Context, docs []source.Document) error {
+	outputPagesDir := ctx.Output()
+	remediationsDir := ctx.Input("remediations")
+	log.Printf("generating cloudsploit pages in: %s...", outputPagesDir)
 
 	titleRegex := regexp.MustCompile(`(?m)^\s+title:\s?'(.*)'`)
 	categoryRegex := regexp.MustCompile(`(?m)^\s+category:\s?'(.*)'`)
@@ -39,37 +71,19 @@ func generateCloudSploitPages(inputPagesDir, outputPagesDir, remediationsDir str
 	linkRegex := regexp.MustCompile(`(?m)^\s+link:\s?'(.*)'`)
 	recommendedActionsRegex := regexp.MustCompile(`(?m)^\s+recommended_action:\s?'(.*)'`)
 
-	for _, file := range fileList {
-
-		if strings.HasSuffix(file, ".spec.js") {
-			// not interested in spec files
-			continue
-		}
-
+	for _, doc := range docs {
+		file := doc.Path
 		fullPath := strings.Split(file, "plugins/")[1]
 		provider := strings.Split(fullPath, "/")[0]
 
-		b, err := ioutil.ReadFile(file)
-		if err != nil {
-			fmt.Printf("Error reading %s\n", file)
-			continue
-		}
-
-		id := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
-		if slices.Contains(cloudsploitIgnores, id) {
-			fmt.Printf("Skipping '%s' because it is in the cloudsploit ignore list: \n", id)
-
-			continue
-		}
-
-		content := string(b)
+		content := doc.Data.(string)
 
 		var title, originalCategory, category, description, severity, moreInfo, link, recommendedActions, remediationString string
 
 		if titleRegex.MatchString(content) {
 			title = titleRegex.FindStringSubmatch(content)[1]
 			if title == "" {
-				fmt.Printf("Error, title not found for file :%s\n", file)
+				ctx.RecordError(file, report.StageParse, fmt.Errorf("title not found"))
 				continue
 			}
 		}
@@ -123,13 +137,13 @@ func generateCloudSploitPages(inputPagesDir, outputPagesDir, remediationsDir str
 
 		outputFilePath := filepath.Join(outputPagesDir, providerID, aliasCategoryID, strings.ToLower(fmt.Sprintf("%s.md", remediationString)))
 		if err := os.MkdirAll(filepath.Dir(outputFilePath), 0755); err != nil {
-			fmt.Printf("Could not create directory for %s\n", outputFilePath)
+			ctx.RecordError(file, report.StageWrite, err)
 			continue
 		}
 
 		outputFile, err := os.Create(outputFilePath)
 		if err != nil {
-			fmt.Printf("failed to create file %s\n", outputFilePath)
+			ctx.RecordError(file, report.StageWrite, err)
 			continue
 		}
 
@@ -160,18 +174,21 @@ func generateCloudSploitPages(inputPagesDir, outputPagesDir, remediationsDir str
 		}
 
 		t := template.Must(template.New("defsecPost").Parse(cspmTemplate))
-		if err := t.Execute(outputFile, post); err != nil {
-			fail(err)
+		err = t.Execute(outputFile, post)
+		_ = outputFile.Close()
+		if err != nil {
+			ctx.RecordError(file, report.StageWrite, err)
+			continue
 		}
 
-		misConfigurationMenu.AddNode(providerID, provider, outputPagesDir, "", []string{},
+		ctx.Menu.AddNode(providerID, provider, outputPagesDir, "", []string{},
 			[]menu.BreadCrumb{}, providerID, true)
-		misConfigurationMenu.AddNode(aliasCategoryID, category, filepath.Join(outputPagesDir, providerID),
+		ctx.Menu.AddNode(aliasCategoryID, category, filepath.Join(outputPagesDir, providerID),
 			providerID, []string{},
 			[]menu.BreadCrumb{{Name: util.Nicify(strings.Title(provider)), Url: fmt.Sprintf("/misconfig/%s", providerID)}}, providerID, false)
 
 	}
-
+	return nil
 }
 
 func hasDefsecOverride(remediationFile string) bool {
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -13,9 +14,13 @@ import (
 func TestGenerateCloudtSploitPages(t *testing.T) {
 	pagesDir := t.TempDir()
 
-	misConfigurationMenu = menu.New("misconfig", pagesDir)
+	ctx := newTestContext(map[string]string{
+		"plugins":      "../goldens/cloudsploit/plugins",
+		"remediations": "../goldens/cloudsploit/en",
+	}, pagesDir, menu.New("misconfig", pagesDir))
+	result := source.Run(ctx, cloudSploitSource{})
+	require.NoError(t, result.Err)
 
-	generateCloudSploitPages("../goldens/cloudsploit/plugins", pagesDir, "../goldens/cloudsploit/en")
 	got, err := ioutil.ReadFile(filepath.Join(pagesDir, "aws/acm/acm-certificate-validation.md"))
 	require.NoError(t, err)
 
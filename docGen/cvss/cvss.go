@@ -0,0 +1,196 @@
+// Package cvss parses CVSS v2, v3.x and v4.0 vectors, recomputes their base
+// scores and describes their base metrics.
+package cvss
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Metric is a base metric of a vector, such as Attack Vector: Network.
+type Metric struct {
+	Abbreviation string
+	Name         string
+	Value        string
+	Meaning      string
+}
+
+type value struct {
+	code    string
+	meaning string
+	weight  float64
+}
+
+type metricDef struct {
+	abbreviation string
+	name         string
+	values       []value
+}
+
+func (d metricDef) value(code string) (value, bool) {
+	for _, v := range d.values {
+		if v.code == code {
+			return v, true
+		}
+	}
+	return value{}, false
+}
+
+// Vector is a parsed and validated CVSS vector.
+type Vector struct {
+	// Version is 2.0, 3.0, 3.1 or 4.0.
+	Version string
+	raw     string
+	metrics map[string]string
+}
+
+// Parse validates a vector. Every base metric must be present, with a valid
+// value, and other metrics must be known to the version.
+func Parse(vector string) (*Vector, error) {
+	v := &Vector{raw: vector, metrics: make(map[string]string)}
+
+	parts := strings.Split(vector, "/")
+	switch parts[0] {
+	case "CVSS:2.0":
+		v.Version, parts = "2.0", parts[1:]
+	case "CVSS:3.0", "CVSS:3.1", "CVSS:4.0":
+		v.Version, parts = strings.TrimPrefix(parts[0], "CVSS:"), parts[1:]
+	default:
+		if strings.HasPrefix(parts[0], "CVSS:") {
+			return nil, fmt.Errorf("unsupported CVSS version %q", strings.TrimPrefix(parts[0], "CVSS:"))
+		}
+		// NVD publishes v2 vectors without a prefix
+		v.Version = "2.0"
+	}
+
+	base, other := v.definitions()
+	for _, part := range parts {
+		k, val, ok := strings.Cut(part, ":")
+		if !ok || k == "" || val == "" {
+			return nil, fmt.Errorf("malformed metric %q in %q", part, vector)
+		}
+		if _, ok := v.metrics[k]; ok {
+			return nil, fmt.Errorf("metric %s is repeated in %q", k, vector)
+		}
+		if !isBase(base, k) && !other[k] {
+			return nil, fmt.Errorf("unknown CVSS %s metric %s in %q", v.Version, k, vector)
+		}
+		v.metrics[k] = val
+	}
+
+	for _, d := range base {
+		code, ok := v.metrics[d.abbreviation]
+		if !ok {
+			return nil, fmt.Errorf("base metric %s is missing from %q", d.abbreviation, vector)
+		}
+		if _, ok := d.value(code); !ok {
+			return nil, fmt.Errorf("invalid value %q for metric %s in %q", code, d.abbreviation, vector)
+		}
+	}
+	return v, nil
+}
+
+func isBase(defs []metricDef, abbreviation string) bool {
+	for _, d := range defs {
+		if d.abbreviation == abbreviation {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *Vector) definitions() ([]metricDef, map[string]bool) {
+	switch v.Version {
+	case "2.0":
+		return v2Base, v2Other
+	case "4.0":
+		return v4Base, v4Other
+	default:
+		return v3Base, v3Other
+	}
+}
+
+func (v *Vector) String() string {
+	return v.raw
+}
+
+// Base returns the base metrics of the vector in the order the specification lists them.
+func (v *Vector) Base() []Metric {
+	base, _ := v.definitions()
+	metrics := make([]Metric, 0, len(base))
+	for _, d := range base {
+		val, _ := d.value(v.metrics[d.abbreviation])
+		metrics = append(metrics, Metric{
+			Abbreviation: d.abbreviation,
+			Name:         d.name,
+			Value:        val.code,
+			Meaning:      val.meaning,
+		})
+	}
+	return metrics
+}
+
+func (v *Vector) weight(abbreviation string) float64 {
+	base, _ := v.definitions()
+	for _, d := range base {
+		if d.abbreviation == abbreviation {
+			val, _ := d.value(v.metrics[abbreviation])
+			return val.weight
+		}
+	}
+	return 0
+}
+
+// BaseScore recomputes the score of the vector. v4.0 scores take the threat
+// and environmental metrics of the vector into account, as those published
+// with it do. The second result is false for versions that can't be scored.
+func (v *Vector) BaseScore() (float64, bool) {
+	switch v.Version {
+	case "2.0":
+		return v.v2BaseScore(), true
+	case "3.0", "3.1":
+		return v.v3BaseScore(), true
+	case "4.0":
+		return v.v4BaseScore(), true
+	}
+	return 0, false
+}
+
+// Check recomputes the base score of the vector and reports a published score
+// that doesn't match it.
+func (v *Vector) Check(published float64) error {
+	score, ok := v.BaseScore()
+	if !ok {
+		return nil
+	}
+	if math.Abs(score-published) > 0.05 {
+		return fmt.Errorf("published score %.1f doesn't match %.1f computed from %s", published, score, v.raw)
+	}
+	return nil
+}
+
+// Severity returns the qualitative rating of a score of the given version.
+func Severity(version string, score float64) string {
+	if version == "2.0" {
+		switch {
+		case score >= 7:
+			return "HIGH"
+		case score >= 4:
+			return "MEDIUM"
+		}
+		return "LOW"
+	}
+
+	switch {
+	case score >= 9:
+		return "CRITICAL"
+	case score >= 7:
+		return "HIGH"
+	case score >= 4:
+		return "MEDIUM"
+	case score > 0:
+		return "LOW"
+	}
+	return "NONE"
+}
@@ -0,0 +1,114 @@
+package cvss
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseScore(t *testing.T) {
+	testCases := []struct {
+		vector   string
+		score    float64
+		severity string
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8, "CRITICAL"},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 10, "CRITICAL"},
+		{"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H", 7.8, "HIGH"},
+		{"CVSS:3.1/AV:L/AC:L/PR:H/UI:N/S:U/C:L/I:N/A:N", 2.3, "LOW"},
+		{"CVSS:3.1/AV:L/AC:L/PR:L/UI:R/S:U/C:N/I:L/A:L", 3.9, "LOW"},
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:L/I:L/A:N", 6.4, "MEDIUM"},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0, "NONE"},
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1, "MEDIUM"},
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N/E:P/RL:O", 6.1, "MEDIUM"},
+		{"AV:N/AC:M/Au:N/C:C/I:C/A:C", 9.3, "HIGH"},
+		{"AV:L/AC:L/Au:N/C:P/I:N/A:N", 2.1, "LOW"},
+		{"AV:N/AC:L/Au:N/C:P/I:P/A:P", 7.5, "HIGH"},
+		{"AV:N/AC:M/Au:N/C:P/I:N/A:N", 4.3, "MEDIUM"},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H", 10, "CRITICAL"},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 9.3, "CRITICAL"},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 8.7, "HIGH"},
+		{"CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 8.5, "HIGH"},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:L/VI:L/VA:L/SC:N/SI:N/SA:N", 6.9, "MEDIUM"},
+		{"CVSS:4.0/AV:N/AC:H/AT:N/PR:N/UI:N/VC:L/VI:N/VA:N/SC:N/SI:N/SA:N", 6.3, "MEDIUM"},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:L/UI:N/VC:L/VI:L/VA:L/SC:N/SI:N/SA:N", 5.3, "MEDIUM"},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:L/UI:P/VC:N/VI:L/VA:N/SC:N/SI:N/SA:N", 5.1, "MEDIUM"},
+		{"CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:L/VI:L/VA:L/SC:N/SI:N/SA:N", 4.8, "MEDIUM"},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:N/VA:N/SC:N/SI:N/SA:N", 0, "NONE"},
+	}
+	for _, tc := range testCases {
+		v, err := Parse(tc.vector)
+		require.NoError(t, err, tc.vector)
+		score, ok := v.BaseScore()
+		require.True(t, ok, tc.vector)
+		assert.Equal(t, tc.score, score, tc.vector)
+		assert.Equal(t, tc.severity, Severity(v.Version, score), tc.vector)
+		assert.NoError(t, v.Check(tc.score), tc.vector)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	v, err := Parse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+	require.NoError(t, err)
+	assert.EqualError(t, v.Check(7.5), "published score 7.5 doesn't match 9.8 computed from CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+
+	v, err = Parse("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N")
+	require.NoError(t, err)
+	assert.NoError(t, v.Check(9.3))
+	assert.EqualError(t, v.Check(10), "published score 10.0 doesn't match 9.3 computed from CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N")
+
+	// the threat and environmental metrics are part of a v4.0 score
+	v, err = Parse("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:U")
+	require.NoError(t, err)
+	assert.Error(t, v.Check(9.3))
+	v, err = Parse("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/MAV:L/MPR:L")
+	require.NoError(t, err)
+	assert.NoError(t, v.Check(8.5))
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := map[string]string{
+		"CVSS:5.0/AV:N": `unsupported CVSS version "5.0"`,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H":          `base metric A is missing from "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H"`,
+		"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H":      `invalid value "X" for metric AV in "CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"`,
+		"CVSS:3.1/AV:N/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H": `metric AV is repeated in "CVSS:3.1/AV:N/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"`,
+		"AV:N/AC:L/Au:N/C:P/I:P/A:P/XX:Y":                   `unknown CVSS 2.0 metric XX in "AV:N/AC:L/Au:N/C:P/I:P/A:P/XX:Y"`,
+		"AV:N/AC:L/Au":                                      `malformed metric "Au" in "AV:N/AC:L/Au"`,
+		"":                                                  `malformed metric "" in ""`,
+	}
+	for vector, expected := range testCases {
+		_, err := Parse(vector)
+		assert.EqualError(t, err, expected, vector)
+	}
+}
+
+func TestBase(t *testing.T) {
+	v, err := Parse("CVSS:4.0/AV:N/AC:L/AT:P/PR:L/UI:A/VC:H/VI:L/VA:N/SC:N/SI:N/SA:N/E:A")
+	require.NoError(t, err)
+	assert.Equal(t, "4.0", v.Version)
+	assert.Equal(t, []Metric{
+		{"AV", "Attack Vector", "N", "Network"},
+		{"AC", "Attack Complexity", "L", "Low"},
+		{"AT", "Attack Requirements", "P", "Present"},
+		{"PR", "Privileges Required", "L", "Low"},
+		{"UI", "User Interaction", "A", "Active"},
+		{"VC", "Vulnerable System Confidentiality", "H", "High"},
+		{"VI", "Vulnerable System Integrity", "L", "Low"},
+		{"VA", "Vulnerable System Availability", "N", "None"},
+		{"SC", "Subsequent System Confidentiality", "N", "None"},
+		{"SI", "Subsequent System Integrity", "N", "None"},
+		{"SA", "Subsequent System Availability", "N", "None"},
+	}, v.Base())
+
+	v, err = Parse("AV:A/AC:H/Au:M/C:N/I:P/A:C")
+	require.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{"AV", "Access Vector", "A", "Adjacent Network"},
+		{"AC", "Access Complexity", "H", "High"},
+		{"Au", "Authentication", "M", "Multiple"},
+		{"C", "Confidentiality Impact", "N", "None"},
+		{"I", "Integrity Impact", "P", "Partial"},
+		{"A", "Availability Impact", "C", "Complete"},
+	}, v.Base())
+}
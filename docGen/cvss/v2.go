@@ -0,0 +1,28 @@
+package cvss
+
+import "math"
+
+var v2Base = []metricDef{
+	{"AV", "Access Vector", []value{{"L", "Local", 0.395}, {"A", "Adjacent Network", 0.646}, {"N", "Network", 1}}},
+	{"AC", "Access Complexity", []value{{"H", "High", 0.35}, {"M", "Medium", 0.61}, {"L", "Low", 0.71}}},
+	{"Au", "Authentication", []value{{"M", "Multiple", 0.45}, {"S", "Single", 0.56}, {"N", "None", 0.704}}},
+	{"C", "Confidentiality Impact", []value{{"N", "None", 0}, {"P", "Partial", 0.275}, {"C", "Complete", 0.66}}},
+	{"I", "Integrity Impact", []value{{"N", "None", 0}, {"P", "Partial", 0.275}, {"C", "Complete", 0.66}}},
+	{"A", "Availability Impact", []value{{"N", "None", 0}, {"P", "Partial", 0.275}, {"C", "Complete", 0.66}}},
+}
+
+// v2Other are the temporal and environmental metrics.
+var v2Other = map[string]bool{
+	"E": true, "RL": true, "RC": true,
+	"CDP": true, "TD": true, "CR": true, "IR": true, "AR": true,
+}
+
+func (v *Vector) v2BaseScore() float64 {
+	impact := 10.41 * (1 - (1-v.weight("C"))*(1-v.weight("I"))*(1-v.weight("A")))
+	exploitability := 20 * v.weight("AV") * v.weight("AC") * v.weight("Au")
+	f := 1.176
+	if impact == 0 {
+		f = 0
+	}
+	return math.Round(((0.6*impact)+(0.4*exploitability)-1.5)*f*10) / 10
+}
@@ -0,0 +1,67 @@
+package cvss
+
+import "math"
+
+var v3Base = []metricDef{
+	{"AV", "Attack Vector", []value{{"N", "Network", 0.85}, {"A", "Adjacent", 0.62}, {"L", "Local", 0.55}, {"P", "Physical", 0.2}}},
+	{"AC", "Attack Complexity", []value{{"L", "Low", 0.77}, {"H", "High", 0.44}}},
+	// the weights of PR are those of an unchanged scope, see privilegesRequired
+	{"PR", "Privileges Required", []value{{"N", "None", 0.85}, {"L", "Low", 0.62}, {"H", "High", 0.27}}},
+	{"UI", "User Interaction", []value{{"N", "None", 0.85}, {"R", "Required", 0.62}}},
+	{"S", "Scope", []value{{"U", "Unchanged", 0}, {"C", "Changed", 0}}},
+	{"C", "Confidentiality", []value{{"H", "High", 0.56}, {"L", "Low", 0.22}, {"N", "None", 0}}},
+	{"I", "Integrity", []value{{"H", "High", 0.56}, {"L", "Low", 0.22}, {"N", "None", 0}}},
+	{"A", "Availability", []value{{"H", "High", 0.56}, {"L", "Low", 0.22}, {"N", "None", 0}}},
+}
+
+// v3Other are the temporal and environmental metrics.
+var v3Other = map[string]bool{
+	"E": true, "RL": true, "RC": true,
+	"CR": true, "IR": true, "AR": true,
+	"MAV": true, "MAC": true, "MPR": true, "MUI": true, "MS": true, "MC": true, "MI": true, "MA": true,
+}
+
+func (v *Vector) privilegesRequired(changed bool) float64 {
+	if changed {
+		switch v.metrics["PR"] {
+		case "L":
+			return 0.68
+		case "H":
+			return 0.5
+		}
+	}
+	return v.weight("PR")
+}
+
+func (v *Vector) v3BaseScore() float64 {
+	changed := v.metrics["S"] == "C"
+	iss := 1 - (1-v.weight("C"))*(1-v.weight("I"))*(1-v.weight("A"))
+
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0
+	}
+
+	exploitability := 8.22 * v.weight("AV") * v.weight("AC") * v.privilegesRequired(changed) * v.weight("UI")
+	score := impact + exploitability
+	if changed {
+		score *= 1.08
+	}
+	return v.roundUp(math.Min(score, 10))
+}
+
+// roundUp rounds up to one decimal. 3.1 defines it so that floating point
+// errors don't round up numbers like 4.000000000000001.
+func (v *Vector) roundUp(x float64) float64 {
+	if v.Version == "3.0" {
+		return math.Ceil(x*10) / 10
+	}
+	i := math.Round(x * 100000)
+	if math.Mod(i, 10000) == 0 {
+		return i / 100000
+	}
+	return (math.Floor(i/10000) + 1) / 10
+}
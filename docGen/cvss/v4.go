@@ -0,0 +1,309 @@
+package cvss
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+var v4Base = []metricDef{
+	{"AV", "Attack Vector", []value{{"N", "Network", 0}, {"A", "Adjacent", 0.1}, {"L", "Local", 0.2}, {"P", "Physical", 0.3}}},
+	{"AC", "Attack Complexity", []value{{"L", "Low", 0}, {"H", "High", 0.1}}},
+	{"AT", "Attack Requirements", []value{{"N", "None", 0}, {"P", "Present", 0.1}}},
+	{"PR", "Privileges Required", []value{{"N", "None", 0}, {"L", "Low", 0.1}, {"H", "High", 0.2}}},
+	{"UI", "User Interaction", []value{{"N", "None", 0}, {"P", "Passive", 0.1}, {"A", "Active", 0.2}}},
+	{"VC", "Vulnerable System Confidentiality", []value{{"H", "High", 0}, {"L", "Low", 0.1}, {"N", "None", 0.2}}},
+	{"VI", "Vulnerable System Integrity", []value{{"H", "High", 0}, {"L", "Low", 0.1}, {"N", "None", 0.2}}},
+	{"VA", "Vulnerable System Availability", []value{{"H", "High", 0}, {"L", "Low", 0.1}, {"N", "None", 0.2}}},
+	{"SC", "Subsequent System Confidentiality", []value{{"H", "High", 0.1}, {"L", "Low", 0.2}, {"N", "None", 0.3}}},
+	{"SI", "Subsequent System Integrity", []value{{"H", "High", 0.1}, {"L", "Low", 0.2}, {"N", "None", 0.3}}},
+	{"SA", "Subsequent System Availability", []value{{"H", "High", 0.1}, {"L", "Low", 0.2}, {"N", "None", 0.3}}},
+}
+
+// v4Other are the threat, environmental and supplemental metrics.
+var v4Other = map[string]bool{
+	"E":  true,
+	"CR": true, "IR": true, "AR": true,
+	"MAV": true, "MAC": true, "MAT": true, "MPR": true, "MUI": true,
+	"MVC": true, "MVI": true, "MVA": true, "MSC": true, "MSI": true, "MSA": true,
+	"S": true, "AU": true, "R": true, "V": true, "RE": true, "U": true,
+}
+
+// v4Levels are the severity levels of the metrics a v4.0 score is interpolated
+// with, lower being more severe. The base metrics have theirs as weights.
+var v4Levels = map[string]map[string]float64{
+	"SI": {"S": 0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"SA": {"S": 0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"CR": {"H": 0, "M": 0.1, "L": 0.2},
+	"IR": {"H": 0, "M": 0.1, "L": 0.2},
+	"AR": {"H": 0, "M": 0.1, "L": 0.2},
+}
+
+// v4Lookup is the score of every macrovector, the levels of the six
+// equivalence classes of the specification, from the lookup table of the
+// FIRST calculator at https://www.first.org/cvss/calculator/4.0.
+var v4Lookup = map[string]float64{
+	"000000": 10, "000001": 9.9, "000010": 9.8, "000011": 9.5, "000020": 9.5, "000021": 9.2,
+	"000100": 10, "000101": 9.6, "000110": 9.3, "000111": 8.7, "000120": 9.1, "000121": 8.1,
+	"000200": 9.3, "000201": 9, "000210": 8.9, "000211": 8, "000220": 8.1, "000221": 6.8,
+	"001000": 9.8, "001001": 9.5, "001010": 9.5, "001011": 9.2, "001020": 9, "001021": 8.4,
+	"001100": 9.3, "001101": 9.2, "001110": 8.9, "001111": 8.1, "001120": 8.1, "001121": 6.5,
+	"001200": 8.8, "001201": 8, "001210": 7.8, "001211": 7, "001220": 6.9, "001221": 4.8,
+	"002001": 9.2, "002011": 8.2, "002021": 7.2, "002101": 7.9, "002111": 6.9, "002121": 5,
+	"002201": 6.9, "002211": 5.5, "002221": 2.7, "010000": 9.9, "010001": 9.7, "010010": 9.5,
+	"010011": 9.2, "010020": 9.2, "010021": 8.5, "010100": 9.5, "010101": 9.1, "010110": 9,
+	"010111": 8.3, "010120": 8.4, "010121": 7.1, "010200": 9.2, "010201": 8.1, "010210": 8.2,
+	"010211": 7.1, "010220": 7.2, "010221": 5.3, "011000": 9.5, "011001": 9.3, "011010": 9.2,
+	"011011": 8.5, "011020": 8.5, "011021": 7.3, "011100": 9.2, "011101": 8.2, "011110": 8,
+	"011111": 7.2, "011120": 7, "011121": 5.9, "011200": 8.4, "011201": 7, "011210": 7.1,
+	"011211": 5.2, "011220": 5, "011221": 3, "012001": 8.6, "012011": 7.5, "012021": 5.2,
+	"012101": 7.1, "012111": 5.2, "012121": 2.9, "012201": 6.3, "012211": 2.9, "012221": 1.7,
+	"100000": 9.8, "100001": 9.5, "100010": 9.4, "100011": 8.7, "100020": 9.1, "100021": 8.1,
+	"100100": 9.4, "100101": 8.9, "100110": 8.6, "100111": 7.4, "100120": 7.7, "100121": 6.4,
+	"100200": 8.7, "100201": 7.5, "100210": 7.4, "100211": 6.3, "100220": 6.3, "100221": 4.9,
+	"101000": 9.4, "101001": 8.9, "101010": 8.8, "101011": 7.7, "101020": 7.6, "101021": 6.7,
+	"101100": 8.6, "101101": 7.6, "101110": 7.4, "101111": 5.8, "101120": 5.9, "101121": 5,
+	"101200": 7.2, "101201": 5.7, "101210": 5.7, "101211": 5.2, "101220": 5.2, "101221": 2.5,
+	"102001": 8.3, "102011": 7, "102021": 5.4, "102101": 6.5, "102111": 5.8, "102121": 2.6,
+	"102201": 5.3, "102211": 2.1, "102221": 1.3, "110000": 9.5, "110001": 9, "110010": 8.8,
+	"110011": 7.6, "110020": 7.6, "110021": 7, "110100": 9, "110101": 7.7, "110110": 7.5,
+	"110111": 6.2, "110120": 6.1, "110121": 5.3, "110200": 7.7, "110201": 6.6, "110210": 6.8,
+	"110211": 5.9, "110220": 5.2, "110221": 3, "111000": 8.9, "111001": 7.8, "111010": 7.6,
+	"111011": 6.7, "111020": 6.2, "111021": 5.8, "111100": 7.4, "111101": 5.9, "111110": 5.7,
+	"111111": 5.7, "111120": 4.7, "111121": 2.3, "111200": 6.1, "111201": 5.2, "111210": 5.7,
+	"111211": 2.9, "111220": 2.4, "111221": 1.6, "112001": 7.1, "112011": 5.9, "112021": 3,
+	"112101": 5.8, "112111": 2.6, "112121": 1.5, "112201": 2.3, "112211": 1.3, "112221": 0.6,
+	"200000": 9.3, "200001": 8.7, "200010": 8.6, "200011": 7.2, "200020": 7.5, "200021": 5.8,
+	"200100": 8.6, "200101": 7.4, "200110": 7.4, "200111": 6.1, "200120": 5.6, "200121": 3.4,
+	"200200": 7, "200201": 5.4, "200210": 5.2, "200211": 4, "200220": 4, "200221": 2.2,
+	"201000": 8.5, "201001": 7.5, "201010": 7.4, "201011": 5.5, "201020": 6.2, "201021": 5.1,
+	"201100": 7.2, "201101": 5.7, "201110": 5.5, "201111": 4.1, "201120": 4.6, "201121": 1.9,
+	"201200": 5.3, "201201": 3.6, "201210": 3.4, "201211": 1.9, "201220": 1.9, "201221": 0.8,
+	"202001": 6.4, "202011": 5.1, "202021": 2, "202101": 4.7, "202111": 2.1, "202121": 1.1,
+	"202201": 2.4, "202211": 0.9, "202221": 0.4, "210000": 8.8, "210001": 7.5, "210010": 7.3,
+	"210011": 5.3, "210020": 6, "210021": 5, "210100": 7.3, "210101": 5.5, "210110": 5.9,
+	"210111": 4, "210120": 4.1, "210121": 2, "210200": 5.4, "210201": 4.3, "210210": 4.5,
+	"210211": 2.2, "210220": 2, "210221": 1.1, "211000": 7.5, "211001": 5.5, "211010": 5.8,
+	"211011": 4.5, "211020": 4, "211021": 2.1, "211100": 6.1, "211101": 5.1, "211110": 4.8,
+	"211111": 1.8, "211120": 2, "211121": 0.9, "211200": 4.6, "211201": 1.8, "211210": 1.7,
+	"211211": 0.7, "211220": 0.8, "211221": 0.2, "212001": 5.3, "212011": 2.4, "212021": 1.4,
+	"212101": 2.4, "212111": 1.2, "212121": 0.5, "212201": 1, "212211": 0.3, "212221": 0.1,
+}
+
+// v4MaxVectors are the most severe vectors of each level of an equivalence
+// class, EQ3 and EQ6 being scored together.
+var v4MaxVectors = struct {
+	eq1, eq2, eq4, eq5 [][]string
+	eq3eq6             [][][]string
+}{
+	eq1: [][]string{{"AV:N/PR:N/UI:N"}, {"AV:A/PR:N/UI:N", "AV:N/PR:L/UI:N", "AV:N/PR:N/UI:P"}, {"AV:P/PR:N/UI:N", "AV:A/PR:L/UI:P"}},
+	eq2: [][]string{{"AC:L/AT:N"}, {"AC:H/AT:N", "AC:L/AT:P"}},
+	eq3eq6: [][][]string{
+		{{"VC:H/VI:H/VA:H/CR:H/IR:H/AR:H"}, {"VC:H/VI:H/VA:L/CR:M/IR:M/AR:H", "VC:H/VI:H/VA:H/CR:M/IR:M/AR:M"}},
+		{{"VC:L/VI:H/VA:H/CR:H/IR:H/AR:H", "VC:H/VI:L/VA:H/CR:H/IR:H/AR:H"}, {"VC:L/VI:H/VA:L/CR:H/IR:M/AR:H", "VC:L/VI:H/VA:H/CR:H/IR:M/AR:M", "VC:H/VI:L/VA:H/CR:M/IR:H/AR:M", "VC:H/VI:L/VA:L/CR:M/IR:H/AR:H", "VC:L/VI:L/VA:H/CR:H/IR:H/AR:M"}},
+		{nil, {"VC:L/VI:L/VA:L/CR:H/IR:H/AR:H"}},
+	},
+	eq4: [][]string{{"SC:H/SI:S/SA:S"}, {"SC:H/SI:H/SA:H"}, {"SC:L/SI:L/SA:L"}},
+	eq5: [][]string{{"E:A"}, {"E:P"}, {"E:U"}},
+}
+
+// v4MaxSeverity is the severity distance, in steps of 0.1, from the most
+// severe to the least severe vector of each level of an equivalence class.
+var v4MaxSeverity = struct {
+	eq1, eq2, eq4 []float64
+	eq3eq6        [][]float64
+}{
+	eq1:    []float64{1, 4, 5},
+	eq2:    []float64{1, 2},
+	eq3eq6: [][]float64{{7, 6}, {8, 8}, {0, 10}},
+	eq4:    []float64{6, 5, 4},
+}
+
+// v4Metric is the value of a metric that a v4.0 score is computed from: the
+// modified metric when the vector sets one, otherwise the metric itself or,
+// for an unset threat or security requirement, the value the specification
+// assumes for it.
+func (v *Vector) v4Metric(abbreviation string) string {
+	if m, ok := v.metrics["M"+abbreviation]; ok && m != "X" {
+		return m
+	}
+	if m, ok := v.metrics[abbreviation]; ok && m != "X" {
+		return m
+	}
+	switch abbreviation {
+	case "E":
+		return "A"
+	case "CR", "IR", "AR":
+		return "H"
+	}
+	return ""
+}
+
+// v4Level is the severity level of a metric's value.
+func v4Level(abbreviation, code string) float64 {
+	if levels, ok := v4Levels[abbreviation]; ok {
+		return levels[code]
+	}
+	for _, d := range v4Base {
+		if d.abbreviation == abbreviation {
+			val, _ := d.value(code)
+			return val.weight
+		}
+	}
+	return 0
+}
+
+// v4MacroVector returns the levels of the equivalence classes EQ1 to EQ6 of the vector.
+func (v *Vector) v4MacroVector() [6]int {
+	m := v.v4Metric
+	var eq [6]int
+
+	switch {
+	case m("AV") == "N" && m("PR") == "N" && m("UI") == "N":
+		eq[0] = 0
+	case (m("AV") == "N" || m("PR") == "N" || m("UI") == "N") && m("AV") != "P":
+		eq[0] = 1
+	default:
+		eq[0] = 2
+	}
+
+	if m("AC") != "L" || m("AT") != "N" {
+		eq[1] = 1
+	}
+
+	switch {
+	case m("VC") == "H" && m("VI") == "H":
+		eq[2] = 0
+	case m("VC") == "H" || m("VI") == "H" || m("VA") == "H":
+		eq[2] = 1
+	default:
+		eq[2] = 2
+	}
+
+	switch {
+	case m("SI") == "S" || m("SA") == "S":
+		eq[3] = 0
+	case m("SC") == "H" || m("SI") == "H" || m("SA") == "H":
+		eq[3] = 1
+	default:
+		eq[3] = 2
+	}
+
+	switch m("E") {
+	case "P":
+		eq[4] = 1
+	case "U":
+		eq[4] = 2
+	}
+
+	if !(m("CR") == "H" && m("VC") == "H" || m("IR") == "H" && m("VI") == "H" || m("AR") == "H" && m("VA") == "H") {
+		eq[5] = 1
+	}
+	return eq
+}
+
+func v4Score(eq [6]int) (float64, bool) {
+	score, ok := v4Lookup[fmt.Sprintf("%d%d%d%d%d%d", eq[0], eq[1], eq[2], eq[3], eq[4], eq[5])]
+	return score, ok
+}
+
+// v4BaseScore scores the vector as the reference implementation of FIRST
+// does: the score of its macrovector, lowered by how far the vector is from
+// the most severe one of the macrovector, in proportion to the score of the
+// next less severe macrovector of each equivalence class.
+func (v *Vector) v4BaseScore() float64 {
+	m := v.v4Metric
+	if m("VC") == "N" && m("VI") == "N" && m("VA") == "N" && m("SC") == "N" && m("SI") == "N" && m("SA") == "N" {
+		return 0
+	}
+
+	eq := v.v4MacroVector()
+	value, _ := v4Score(eq)
+	lower := func(deltas ...int) (float64, bool) {
+		next := eq
+		for i, d := range deltas {
+			next[i] += d
+		}
+		return v4Score(next)
+	}
+
+	// the vectors of the macrovector that are the most severe in every class
+	var maxVectors []map[string]string
+	for _, eq1 := range v4MaxVectors.eq1[eq[0]] {
+		for _, eq2 := range v4MaxVectors.eq2[eq[1]] {
+			for _, eq3eq6 := range v4MaxVectors.eq3eq6[eq[2]][eq[5]] {
+				for _, eq4 := range v4MaxVectors.eq4[eq[3]] {
+					for _, eq5 := range v4MaxVectors.eq5[eq[4]] {
+						maxVector := make(map[string]string)
+						for _, part := range strings.Split(strings.Join([]string{eq1, eq2, eq3eq6, eq4, eq5}, "/"), "/") {
+							k, val, _ := strings.Cut(part, ":")
+							maxVector[k] = val
+						}
+						maxVectors = append(maxVectors, maxVector)
+					}
+				}
+			}
+		}
+	}
+
+	// the severity distance of every metric to the first max vector the vector isn't more severe than
+	distance := make(map[string]float64)
+	for _, maxVector := range maxVectors {
+		below := true
+		for _, k := range []string{"AV", "PR", "UI", "AC", "AT", "VC", "VI", "VA", "SC", "SI", "SA", "CR", "IR", "AR"} {
+			distance[k] = v4Level(k, m(k)) - v4Level(k, maxVector[k])
+			below = below && distance[k] >= 0
+		}
+		if below {
+			break
+		}
+	}
+
+	const step = 0.1
+	var eq3eq6Lower float64
+	var eq3eq6OK bool
+	switch {
+	case eq[2] == 0 && eq[5] == 0:
+		// either class can be the next less severe one, the higher score is used
+		left, _ := lower(0, 0, 0, 0, 0, 1)
+		right, _ := lower(0, 0, 1)
+		eq3eq6Lower, eq3eq6OK = math.Max(left, right), true
+	case eq[2] == 1 && eq[5] == 0:
+		eq3eq6Lower, eq3eq6OK = lower(0, 0, 0, 0, 0, 1)
+	case eq[2] < 2:
+		eq3eq6Lower, eq3eq6OK = lower(0, 0, 1)
+	}
+
+	classes := []struct {
+		distance    float64
+		maxSeverity float64
+		lower       float64
+		ok          bool
+	}{
+		{distance["AV"] + distance["PR"] + distance["UI"], v4MaxSeverity.eq1[eq[0]], 0, false},
+		{distance["AC"] + distance["AT"], v4MaxSeverity.eq2[eq[1]], 0, false},
+		{distance["VC"] + distance["VI"] + distance["VA"] + distance["CR"] + distance["IR"] + distance["AR"], v4MaxSeverity.eq3eq6[eq[2]][eq[5]], eq3eq6Lower, eq3eq6OK},
+		{distance["SC"] + distance["SI"] + distance["SA"], v4MaxSeverity.eq4[eq[3]], 0, false},
+		// the max vectors of EQ5 are its only vectors, so the distance to them is 0
+		{0, 1, 0, false},
+	}
+	classes[0].lower, classes[0].ok = lower(1)
+	classes[1].lower, classes[1].ok = lower(0, 1)
+	classes[3].lower, classes[3].ok = lower(0, 0, 0, 1)
+	classes[4].lower, classes[4].ok = lower(0, 0, 0, 0, 1)
+
+	var sum float64
+	var n int
+	for _, c := range classes {
+		if !c.ok {
+			continue
+		}
+		n++
+		sum += (value - c.lower) * c.distance / (c.maxSeverity * step)
+	}
+	if n > 0 {
+		value -= sum / float64(n)
+	}
+	// the epsilon keeps floating point errors such as 4.449999999 from rounding down
+	return math.Round(math.Max(0, math.Min(value, 10))*10+1e-9) / 10
+}
@@ -1,20 +1,57 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"log"
+	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"text/template"
+	"time"
+
+	"github.com/leekchan/gtf"
+	"github.com/valyala/fastjson"
+
+	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
 )
 
 type RelatedAttackPattern struct {
-	CAPECID int
+	CAPECID int `xml:"CAPEC_ID,attr"`
+	// Pattern is set once the attack pattern is resolved from the CAPEC catalog.
+	Pattern *AttackPattern `json:"-"`
 }
 
 // The RelatedAttackPatternsType complex type contains references to attack patterns associated with this weakness. The association implies those attack patterns may be applicable if an instance of this weakness exists. Each related attack pattern is identified by a CAPEC identifier.
 type RelatedAttackPatternsType struct {
-	RelatedAttackPattern []RelatedAttackPattern
+	RelatedAttackPattern []RelatedAttackPattern `xml:"Related_Attack_Pattern"`
+}
+
+// RelatedWeakness is a relation of a weakness to another entry of the CWE
+// catalogue in a view, such as ChildOf, ParentOf or MemberOf a category.
+type RelatedWeakness struct {
+	Nature NatureEnumeration `xml:"Nature,attr"`
+	CWEID  int               `xml:"CWE_ID,attr"`
+	ViewID int               `xml:"View_ID,attr"`
+	// Name and ViewName are set once the relation is resolved from the CWE catalogue.
+	Name     string `json:"-" xml:"-"`
+	ViewName string `json:"-" xml:"-"`
+}
+
+// May be one of ChildOf, ParentOf, StartsWith, CanFollow, CanPrecede, RequiredBy, Requires, CanAlsoBe, PeerOf, and MemberOf for the categories of a weakness
+type NatureEnumeration string
+
+// The RelatedWeaknessesType complex type is used to refer to other weaknesses that differ only in their level of abstraction. It contains one or more Related_Weakness elements, each of which is used to link to the CWE identifier of the other Weakness. The nature of the relation is captured by the Nature attribute.
+type RelatedWeaknessesType struct {
+	RelatedWeakness []RelatedWeakness `xml:"Related_Weakness"`
 }
 
 type Mitigation struct {
@@ -23,6 +60,27 @@ type Mitigation struct {
 	Description StructuredTextType
 }
 
+// Markdown is the mitigation as a list item labelled with its strategy, further
+// paragraphs and code blocks of its description continue the item.
+func (m Mitigation) Markdown() string {
+	var b strings.Builder
+	b.WriteString("-")
+	paragraphs := m.Description.Paragraphs()
+	if m.Strategy != "" {
+		fmt.Fprintf(&b, " **%s**", m.Strategy)
+		if len(paragraphs) > 0 {
+			b.WriteString(":")
+		}
+	}
+	for i, p := range paragraphs {
+		if i > 0 || strings.HasPrefix(p, "```") {
+			b.WriteString("\n\n ")
+		}
+		b.WriteString(" " + strings.ReplaceAll(p, "\n", "\n  "))
+	}
+	return b.String()
+}
+
 // May be one of Policy, Requirements, Architecture and Design, Implementation, Build and Compilation, Testing, Documentation, Bundling, Distribution, Installation, System Configuration, Operation, Patching and Maintenance, Porting, Integration, Manufacturing
 type PhaseEnumeration string
 
@@ -48,6 +106,74 @@ type Consequence struct {
 	Impact []TechnicalImpactEnumeration
 }
 
+// ConsequenceMatrix is the technical impacts of a weakness against the
+// security properties they violate, both in the order consequences first
+// mention them.
+type ConsequenceMatrix struct {
+	Scopes   []ScopeEnumeration
+	Impacts  []TechnicalImpactEnumeration
+	violates map[TechnicalImpactEnumeration]map[ScopeEnumeration]bool
+}
+
+// ConsequenceMatrix crosses the scopes and impacts of the weakness's consequences.
+func (w WeaknessType) ConsequenceMatrix() ConsequenceMatrix {
+	m := ConsequenceMatrix{violates: make(map[TechnicalImpactEnumeration]map[ScopeEnumeration]bool)}
+	for _, c := range w.CommonConsequences.Consequence {
+		// a consequence without an impact has no row to be in
+		if len(c.Impact) == 0 {
+			continue
+		}
+		for _, scope := range c.Scope {
+			if !slices.Contains(m.Scopes, scope) {
+				m.Scopes = append(m.Scopes, scope)
+			}
+		}
+		for _, impact := range c.Impact {
+			if _, ok := m.violates[impact]; !ok {
+				m.Impacts = append(m.Impacts, impact)
+				m.violates[impact] = make(map[ScopeEnumeration]bool)
+			}
+			for _, scope := range c.Scope {
+				m.violates[impact][scope] = true
+			}
+		}
+	}
+	return m
+}
+
+// Violates is whether an impact violates a scope.
+func (m ConsequenceMatrix) Violates(impact TechnicalImpactEnumeration, scope ScopeEnumeration) bool {
+	return m.violates[impact][scope]
+}
+
+// Markdown is the matrix as a table with a row per impact and a column per
+// scope, empty when the weakness has no consequences.
+func (m ConsequenceMatrix) Markdown() string {
+	if len(m.Impacts) == 0 || len(m.Scopes) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("| Impact |")
+	for _, scope := range m.Scopes {
+		fmt.Fprintf(&b, " %s |", scope)
+	}
+	b.WriteString("\n| ------------- |")
+	for range m.Scopes {
+		b.WriteString("-------------|")
+	}
+	for _, impact := range m.Impacts {
+		fmt.Fprintf(&b, "\n| %s |", impact)
+		for _, scope := range m.Scopes {
+			if m.Violates(impact, scope) {
+				b.WriteString(" ✓ |")
+			} else {
+				b.WriteString("  |")
+			}
+		}
+	}
+	return b.String()
+}
+
 // May be one of Modify Memory, Read Memory, Modify Files or Directories, Read Files or Directories, Modify Application Data, Read Application Data, DoS: Crash, Exit, or Restart, DoS: Amplification, DoS: Instability, DoS: Resource Consumption (CPU), DoS: Resource Consumption (Memory), DoS: Resource Consumption (Other), Execute Unauthorized Code or Commands, Gain Privileges or Assume Identity, Bypass Protection Mechanism, Hide Activities, Alter Execution Logic, Quality Degradation, Unexpected State, Varies by Context, Reduce Maintainability, Reduce Performance, Reduce Reliability, Other
 type TechnicalImpactEnumeration string
 
@@ -56,29 +182,311 @@ type ScopeEnumeration string
 
 type StructuredTextType []string
 
+// Paragraphs are the paragraphs of the text that aren't blank.
+func (s StructuredTextType) Paragraphs() []string {
+	var paragraphs []string
+	for _, p := range s {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
+
 type WeaknessType struct {
-	ID                    int
-	Name                  string
+	ID                    int    `xml:"ID,attr"`
+	Name                  string `xml:"Name,attr"`
+	Status                string `xml:"Status,attr"`
 	Description           string
-	PotentialMitigations  PotentialMitigationsType
-	RelatedAttackPatterns RelatedAttackPatternsType
-	CommonConsequences    CommonConsequencesType
-	ExtendedDescription   StructuredTextType
+	PotentialMitigations  PotentialMitigationsType  `xml:"Potential_Mitigations"`
+	RelatedAttackPatterns RelatedAttackPatternsType `xml:"Related_Attack_Patterns"`
+	CommonConsequences    CommonConsequencesType    `xml:"Common_Consequences"`
+	ExtendedDescription   StructuredTextType        `xml:"Extended_Description"`
+	RelatedWeaknesses     RelatedWeaknessesType     `xml:"Related_Weaknesses"`
 }
 
-func AddCWEInformation(bp *VulnerabilityPost, cweDir string) error {
-	b, err := ioutil.ReadFile(filepath.Join(cweDir, fmt.Sprintf("%s.json", bp.Vulnerability.CWEID)))
-	if err != nil {
-		return err
+// MitigationPhase is the mitigations of a weakness that apply in a phase of the development life cycle.
+type MitigationPhase struct {
+	Phase       PhaseEnumeration
+	Mitigations []Mitigation
+}
+
+// MitigationsByPhase groups the mitigations that have a description or a
+// strategy by phase, in the order the phases are first mentioned. A mitigation
+// of several phases is listed under each.
+func (w WeaknessType) MitigationsByPhase() []MitigationPhase {
+	var phases []MitigationPhase
+	index := make(map[PhaseEnumeration]int)
+	for _, m := range w.PotentialMitigations.Mitigation {
+		if m.Strategy == "" && len(m.Description.Paragraphs()) == 0 {
+			continue
+		}
+		for _, phase := range m.Phase {
+			i, ok := index[phase]
+			if !ok {
+				i = len(phases)
+				index[phase] = i
+				phases = append(phases, MitigationPhase{Phase: phase})
+			}
+			phases[i].Mitigations = append(phases[i].Mitigations, m)
+		}
 	}
+	return phases
+}
 
+func loadWeakness(file string) (WeaknessType, error) {
 	var w WeaknessType
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return w, err
+	}
 	if err := json.Unmarshal(b, &w); err != nil {
+		return w, err
+	}
+	return w, nil
+}
+
+// AddCWEInformation loads each CWE of a CVE from cweDir, the name of the first
+// one loaded is the short name of its page. CWEs missing from cweDir are skipped.
+func AddCWEInformation(bp *VulnerabilityPost, cweDir string) error {
+	return addWeaknesses(bp, func(id string) (WeaknessType, error) {
+		return loadWeakness(filepath.Join(cweDir, fmt.Sprintf("%s.json", id)))
+	})
+}
+
+// addWeaknesses sets the information of each CWE of a CVE to what load returns
+// for its ID, CWEs it returns os.ErrNotExist for are skipped.
+func addWeaknesses(bp *VulnerabilityPost, load func(id string) (WeaknessType, error)) error {
+	var errs []error
+	for i, weakness := range bp.Vulnerability.Weaknesses {
+		if !weakness.IsCWE() {
+			continue
+		}
+		w, err := load(weakness.ID)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", weakness.ID, err))
+			continue
+		}
+		if bp.ShortName == "" {
+			bp.ShortName = strings.ReplaceAll(w.Name, "\\", "\\\\")
+		}
+		bp.Vulnerability.Weaknesses[i].Info = w
+	}
+	return errors.Join(errs...)
+}
+
+func init() {
+	source.Register("cwe", func() source.Source { return cweSource{} })
+}
+
+// cweSource renders a page per CWE that lists the CVEs referencing it, so
+// vulnerabilities can be browsed by weakness.
+type cweSource struct{}
+
+// CWEPage is a CWE and the CVEs that reference it.
+type CWEPage struct {
+	Weakness WeaknessType
+	CVEs     []CWEReference
+}
+
+// CWEReference is a CVE that references a CWE.
+type CWEReference struct {
+	CVEID     string
+	Published string
+}
+
+func (cweSource) Load(ctx *source.Context) ([]source.Document, error) {
+	path := ctx.Input("cwe")
+	docs, err := loadWeaknesses(ctx, path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("no CWEs at %s, skipping CWE pages", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cves, err := sharedCVEsByCWE(ctx, ctx.Input("nvd"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to index the CVEs of CWEs: %w", err)
+	}
+
+	capec := loadCAPECInput(ctx)
+
+	for i, doc := range docs {
+		w := doc.Data.(WeaknessType)
+		w.resolveAttackPatterns(capec)
+		docs[i].Data = CWEPage{Weakness: w, CVEs: cves[doc.ID]}
+	}
+	return docs, nil
+}
+
+var cweIndexes dirCache[map[string][]CWEReference]
+
+// sharedCVEsByCWE returns the CVEs of the CWEs of the NVD files under dir,
+// indexing them the first time a source asks for them. They must not be modified.
+func sharedCVEsByCWE(ctx *source.Context, dir string) (map[string][]CWEReference, error) {
+	return cweIndexes.get(dir, func(dir string) (map[string][]CWEReference, error) {
+		return cvesByCWE(ctx, dir)
+	})
+}
+
+// cvesByCWE maps CWE IDs to the CVEs of the NVD files under dir that reference
+// them, most recently published first. Rejected CVEs are left out.
+func cvesByCWE(ctx *source.Context, dir string) (map[string][]CWEReference, error) {
+	files, err := getAllFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byCWE := make(map[string][]CWEReference)
+	var p fastjson.Parser
+	for _, file := range files {
+		if filepath.Ext(file) != ".json" {
+			continue
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			ctx.RecordError(file, report.StageRead, err)
+			continue
+		}
+		v, err := p.ParseBytes(b)
+		if err != nil {
+			ctx.RecordError(file, report.StageParse, err)
+			continue
+		}
+		if string(v.GetStringBytes("vulnStatus")) == "Rejected" {
+			continue
+		}
+		published, _ := time.Parse("2006-01-02T15:04:05", string(v.GetStringBytes("published")))
+		for _, w := range parseWeaknesses(v) {
+			if !w.IsCWE() {
+				continue
+			}
+			byCWE[w.ID] = append(byCWE[w.ID], CWEReference{
+				CVEID:     string(v.GetStringBytes("id")),
+				Published: published.UTC().Format("2006-01-02"),
+			})
+		}
+	}
+
+	for _, cves := range byCWE {
+		sortCWEReferences(cves)
+	}
+	return byCWE, nil
+}
+
+// sortCWEReferences sorts CVEs most recently published first.
+func sortCWEReferences(cves []CWEReference) {
+	sort.Slice(cves, func(i, j int) bool {
+		if cves[i].Published != cves[j].Published {
+			return cves[i].Published > cves[j].Published
+		}
+		return cves[i].CVEID < cves[j].CVEID
+	})
+}
+
+func (cweSource) Render(ctx *source.Context, docs []source.Document) error {
+	// there are no CWEs to list
+	if len(docs) == 0 {
+		return nil
+	}
+	postsDir := ctx.Output()
+	log.Printf("generating CWE pages in: %s", postsDir)
+	if err := os.MkdirAll(postsDir, 0755); err != nil {
 		return err
 	}
 
-	bp.ShortName = strings.ReplaceAll(w.Name, "\\", "\\\\")
+	for _, doc := range docs {
+		pageFile := filepath.Join(postsDir, fmt.Sprintf("%s.md", doc.ID))
+		var page bytes.Buffer
+		if err := CWEPostToMarkdown(doc.Data.(CWEPage), &page, GetCustomContentFromMarkdown(pageFile)); err != nil {
+			ctx.RecordError(doc.Path, report.StageRender, err)
+			continue
+		}
+		if _, err := writeIfChanged(pageFile, page.Bytes()); err != nil {
+			ctx.RecordError(doc.Path, report.StageWrite, err)
+		}
+	}
+
+	return menu.NewTopLevelMenu("CWE", "avd_list", filepath.Join(postsDir, "_index.md")).
+		WithHeading("Weaknesses").
+		WithIcon("aqua").
+		WithCategory("vulnerabilities").
+		WithMenu("none").
+		Generate()
+}
 
-	bp.Vulnerability.CWEInfo = w
+func CWEPostToMarkdown(p CWEPage, w io.Writer, customContent string) error {
+	t := template.Must(template.New("cwePost").Funcs(gtf.GtfTextFuncMap).Parse(cwePostTemplate))
+	if err := t.Execute(w, p); err != nil {
+		return err
+	}
+	if customContent != "" {
+		_, _ = io.WriteString(w, "\n"+customContent)
+	}
 	return nil
 }
+
+const cwePostTemplate = `---
+title: "CWE-{{.Weakness.ID}}"
+
+shortName: {{printf "%q" .Weakness.Name}}
+category: vulnerabilities
+draft: false
+
+avd_page_type: cwe_page
+
+header_subtitle: {{printf "%q" .Weakness.Name}}
+
+sidebar_additional_info_cwe: "https://cwe.mitre.org/data/definitions/{{.Weakness.ID}}.html"
+
+breadcrumbs:
+  - name: CWE
+    path: /cwe
+
+---
+
+{{.Weakness.Description}}
+
+{{- with .Weakness.ExtendedDescription.Paragraphs}}
+### Extended Description{{range $p := .}}
+
+{{$p}}{{end}}
+{{end}}
+
+{{- with .Weakness.Hierarchy}}
+### Relationships{{range $v := .}}
+
+#### {{$v.Title}}{{range $r := $v.Relations}}
+{{$r.Markdown}}{{end}}{{end}}
+{{end}}
+
+{{- with .Weakness.ConsequenceMatrix.Markdown}}
+### Common Consequences
+{{.}}
+{{end}}
+
+{{- with .Weakness.MitigationsByPhase}}
+### Potential Mitigations {.with_icon .mitigations}{{range $p := .}}
+
+#### {{$p.Phase}}{{range $m := $p.Mitigations}}
+{{$m.Markdown}}{{end}}{{end}}
+{{end}}
+
+{{- if .Weakness.RelatedAttackPatterns.RelatedAttackPattern}}
+### Related Attack Patterns {.with_icon .related_patterns}{{range $attack := .Weakness.RelatedAttackPatterns.RelatedAttackPattern}}
+{{$attack.Markdown}}{{end}}
+{{end}}
+### Vulnerabilities
+{{- if .CVEs}}
+| CVE | Published |
+| ------------- |-------------|{{range $c := .CVEs}}
+| [{{$c.CVEID}}](/nvd/{{lower $c.CVEID}}) | {{$c.Published}} |{{end}}
+{{- else}}
+No CVE references this weakness.
+{{- end}}
+
+<!--- Add Aqua content below --->`
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
+)
+
+func TestGenerateCWEPages(t *testing.T) {
+	nvdDir := t.TempDir()
+	b, err := os.ReadFile("../goldens/json/nvd/CVE-2020-0002.json")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(nvdDir, "CVE-2020-0002.json"), b, 0600))
+	// rejected CVEs have no page to list
+	require.NoError(t, os.WriteFile(filepath.Join(nvdDir, "CVE-2020-0003.json"), []byte(`{
+  "id": "CVE-2020-0003",
+  "published": "2020-01-08T19:15:12.843",
+  "vulnStatus": "Rejected",
+  "weaknesses": [{"description": [{"lang": "en", "value": "CWE-416"}]}]
+}`), 0600))
+
+	pagesDir := t.TempDir()
+	result := source.Run(newTestContext(map[string]string{"cwe": "../goldens/cwe", "nvd": nvdDir, "capec": "../goldens/capec/capec.xml"}, pagesDir, nil), cweSource{})
+	require.NoError(t, result.Err)
+	assert.Equal(t, 1, result.Documents)
+
+	got, err := os.ReadFile(filepath.Join(pagesDir, "CWE-416.md"))
+	require.NoError(t, err)
+	assert.Equal(t, `---
+title: "CWE-416"
+
+shortName: "Generation of Error Message Containing Sensitive Information"
+category: vulnerabilities
+draft: false
+
+avd_page_type: cwe_page
+
+header_subtitle: "Generation of Error Message Containing Sensitive Information"
+
+sidebar_additional_info_cwe: "https://cwe.mitre.org/data/definitions/416.html"
+
+breadcrumbs:
+  - name: CWE
+    path: /cwe
+
+---
+
+The software generates an error message that includes sensitive information about its environment, users, or associated data.
+### Extended Description
+
+The sensitive information may be valuable information on its own (such as a password), or it may be useful for launching other, more serious attacks. The error message may be created in different ways:
+
+An attacker may use the contents of error messages to help launch another, more focused attack. For example, an attempt to exploit a path traversal weakness (CWE-22) might yield the full pathname of the installed application. In turn, this could be used to select the proper number of ".." sequences to navigate to the targeted file. An attack using SQL injection (CWE-89) might not initially succeed, but an error message could reveal the malformed query, which would expose query logic and possibly even passwords or other sensitive information used within the query.
+
+### Common Consequences
+| Impact | Confidentiality |
+| ------------- |-------------|
+| Read Application Data | ✓ |
+
+### Potential Mitigations {.with_icon .mitigations}
+
+#### Implementation
+- Ensure that error messages only contain minimal details that are useful to the intended audience, and nobody else. The messages need to strike the balance between being too cryptic and not being cryptic enough. They should not necessarily reveal the methods that were used to determine the error. Such detailed information can be used to refine the original attack to increase the chances of success.
+
+  If errors must be tracked in some detail, capture them in log messages - but consider what could occur if the log messages can be viewed by attackers. Avoid recording highly sensitive information such as passwords in any form. Avoid inconsistent messaging that might accidentally tip off an attacker about internal state, such as whether a username is valid or not.
+- **Attack Surface Reduction**
+- **Compilation or Build Hardening**
+- **Environment Hardening**
+
+#### Build and Compilation
+- **Compilation or Build Hardening**
+- **Environment Hardening**
+
+### Related Attack Patterns {.with_icon .related_patterns}
+- [CAPEC-214](https://capec.mitre.org/data/definitions/214.html)
+- [CAPEC-215](https://capec.mitre.org/data/definitions/215.html)
+- [CAPEC-463: Padding Oracle Crypto Attack](/capec/capec-463)
+  - Severity: High
+- [CAPEC-54: Query System for Information](/capec/capec-54)
+  - Likelihood: High
+  - Severity: Medium
+  - Prerequisites: This class of attacks requires that the adversary be able to interact with the application.
+- [CAPEC-7: Blind SQL Injection](/capec/capec-7)
+  - Likelihood: High
+  - Severity: High
+  - Prerequisites: SQL queries used by the application to store, retrieve or modify data. User-controllable input that is not properly validated by the application as part of SQL queries.
+
+### Vulnerabilities
+| CVE | Published |
+| ------------- |-------------|
+| [CVE-2020-0002](/nvd/cve-2020-0002) | 2020-01-08 |
+
+<!--- Add Aqua content below --->`, string(got))
+
+	index, err := os.ReadFile(filepath.Join(pagesDir, "_index.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "avd_page_type: avd_list")
+}
+
+func TestMitigationsByPhase(t *testing.T) {
+	w := WeaknessType{PotentialMitigations: PotentialMitigationsType{Mitigation: []Mitigation{
+		{Phase: []PhaseEnumeration{"Implementation"}, Description: StructuredTextType{"Validate input."}},
+		{Phase: []PhaseEnumeration{"Architecture and Design", "Implementation"}, Description: StructuredTextType{"Use a vetted library."}},
+		// mitigations without a description or strategy have nothing to show
+		{Phase: []PhaseEnumeration{"Operation"}, Description: StructuredTextType{"\n  "}},
+		{Phase: []PhaseEnumeration{"Build and Compilation"}, Strategy: "Compilation or Build Hardening"},
+	}}}
+	phases := w.MitigationsByPhase()
+	require.Len(t, phases, 3)
+	assert.Equal(t, PhaseEnumeration("Implementation"), phases[0].Phase)
+	assert.Len(t, phases[0].Mitigations, 2)
+	assert.Equal(t, PhaseEnumeration("Architecture and Design"), phases[1].Phase)
+	assert.Len(t, phases[1].Mitigations, 1)
+	assert.Equal(t, "- **Compilation or Build Hardening**", phases[2].Mitigations[0].Markdown())
+	assert.Equal(t, "- **Use a vetted library.**", Mitigation{Strategy: "Use a vetted library."}.Markdown())
+}
+
+func TestConsequenceMatrix(t *testing.T) {
+	w := WeaknessType{CommonConsequences: CommonConsequencesType{Consequence: []Consequence{
+		{Scope: []ScopeEnumeration{"Integrity", "Availability"}, Impact: []TechnicalImpactEnumeration{"Modify Memory", "DoS: Crash, Exit, or Restart"}},
+		{Scope: []ScopeEnumeration{"Confidentiality"}, Impact: []TechnicalImpactEnumeration{"Read Memory", "Modify Memory"}},
+		// a consequence without an impact has no row
+		{Scope: []ScopeEnumeration{"Other"}},
+	}}}
+	m := w.ConsequenceMatrix()
+	assert.Equal(t, []ScopeEnumeration{"Integrity", "Availability", "Confidentiality"}, m.Scopes)
+	assert.True(t, m.Violates("Modify Memory", "Confidentiality"))
+	assert.False(t, m.Violates("Read Memory", "Integrity"))
+	assert.Equal(t, `| Impact | Integrity | Availability | Confidentiality |
+| ------------- |-------------|-------------|-------------|
+| Modify Memory | ✓ | ✓ | ✓ |
+| DoS: Crash, Exit, or Restart | ✓ | ✓ |  |
+| Read Memory |  |  | ✓ |`, m.Markdown())
+
+	assert.Empty(t, WeaknessType{}.ConsequenceMatrix().Markdown())
+}
+
+func TestSharedCVEsByCWE(t *testing.T) {
+	nvdDir := t.TempDir()
+	b, err := os.ReadFile("../goldens/json/nvd/CVE-2020-0002.json")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(nvdDir, "CVE-2020-0002.json"), b, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(nvdDir, "CVE-2020-0003.json"), []byte("{"), 0600))
+
+	// the sources of a run sharing the index read and record the NVD files once
+	rep := report.New()
+	cweCtx := newTestContext(nil, t.TempDir(), nil)
+	capecCtx := newTestContext(nil, t.TempDir(), nil)
+	cweCtx.Source.Name, cweCtx.Report = "cwe", rep
+	capecCtx.Source.Name, capecCtx.Report = "capec", rep
+	for _, ctx := range []*source.Context{cweCtx, capecCtx} {
+		cves, err := sharedCVEsByCWE(ctx, nvdDir)
+		require.NoError(t, err)
+		assert.Equal(t, []CWEReference{{CVEID: "CVE-2020-0002", Published: "2020-01-08"}}, cves["CWE-416"])
+	}
+	assert.Equal(t, 1, rep.Count("cwe", report.StageParse))
+	assert.Equal(t, 0, rep.Count("capec", report.StageParse))
+}
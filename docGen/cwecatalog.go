@@ -0,0 +1,370 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
+)
+
+// cweCatalogXML is the CWE catalogue published at
+// https://cwe.mitre.org/data/downloads.html, unzipped.
+type cweCatalogXML struct {
+	Version    string `xml:"Version,attr"`
+	Weaknesses struct {
+		Weakness []WeaknessType
+	}
+	Categories struct {
+		Category []CWECategory
+	}
+	Views struct {
+		View []CWEView
+	}
+}
+
+// CWECategory is a category of the CWE catalogue, which groups weaknesses that
+// share a characteristic.
+type CWECategory struct {
+	ID            int    `xml:"ID,attr"`
+	Name          string `xml:"Name,attr"`
+	Status        string `xml:"Status,attr"`
+	Summary       StructuredTextType
+	Relationships struct {
+		HasMember []CWEMember `xml:"Has_Member"`
+	}
+}
+
+// CWEView is a view of the CWE catalogue, a perspective such as Research
+// Concepts from which its entries are organized.
+type CWEView struct {
+	ID        int    `xml:"ID,attr"`
+	Name      string `xml:"Name,attr"`
+	Type      string `xml:"Type,attr"`
+	Status    string `xml:"Status,attr"`
+	Objective StructuredTextType
+	Members   struct {
+		HasMember []CWEMember `xml:"Has_Member"`
+	}
+}
+
+// CWEMember is an entry that a category or view has as member in a view.
+type CWEMember struct {
+	CWEID  int `xml:"CWE_ID,attr"`
+	ViewID int `xml:"View_ID,attr"`
+}
+
+// cweCatalog is the weaknesses, categories and views of the CWE catalogue
+// indexed by ID. Deprecated entries are left out.
+type cweCatalog struct {
+	Version    string
+	Weaknesses map[int]WeaknessType
+	Categories map[int]CWECategory
+	Views      map[int]CWEView
+}
+
+// isCWECatalog is whether the cwe input of a source is the XML catalogue rather
+// than the directory of vuln-list/cwe.
+func isCWECatalog(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".xml")
+}
+
+// loadCWECatalog reads the CWE catalogue at path. The catalogue only records
+// that a weakness is ChildOf another and which members a category has, so the
+// ParentOf and MemberOf relations are added to the weaknesses they point to.
+func loadCWECatalog(path string) (*cweCatalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var x cweCatalogXML
+	if err := xml.NewDecoder(f).Decode(&x); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	c := &cweCatalog{
+		Version:    x.Version,
+		Weaknesses: make(map[int]WeaknessType, len(x.Weaknesses.Weakness)),
+		Categories: make(map[int]CWECategory, len(x.Categories.Category)),
+		Views:      make(map[int]CWEView, len(x.Views.View)),
+	}
+	for _, w := range x.Weaknesses.Weakness {
+		if w.ID == 0 || w.Status == "Deprecated" {
+			continue
+		}
+		// the XML is indented, so text spans lines
+		w.Description = strings.Join(strings.Fields(w.Description), " ")
+		c.Weaknesses[w.ID] = w
+	}
+	for _, category := range x.Categories.Category {
+		if category.ID != 0 && category.Status != "Deprecated" {
+			c.Categories[category.ID] = category
+		}
+	}
+	for _, view := range x.Views.View {
+		if view.ID != 0 && view.Status != "Deprecated" {
+			c.Views[view.ID] = view
+		}
+	}
+
+	for _, w := range c.Weaknesses {
+		for _, r := range w.RelatedWeaknesses.RelatedWeakness {
+			if r.Nature == "ChildOf" {
+				c.relate(r.CWEID, RelatedWeakness{Nature: "ParentOf", CWEID: w.ID, ViewID: r.ViewID})
+			}
+		}
+	}
+	for _, category := range c.Categories {
+		for _, m := range category.Relationships.HasMember {
+			c.relate(m.CWEID, RelatedWeakness{Nature: "MemberOf", CWEID: category.ID, ViewID: m.ViewID})
+		}
+	}
+
+	for id, w := range c.Weaknesses {
+		for i, r := range w.RelatedWeaknesses.RelatedWeakness {
+			w.RelatedWeaknesses.RelatedWeakness[i].Name = c.name(r.CWEID)
+			w.RelatedWeaknesses.RelatedWeakness[i].ViewName = c.Views[r.ViewID].Name
+		}
+		c.Weaknesses[id] = w
+	}
+	return c, nil
+}
+
+// relate adds a relation to the weakness of an ID, unless the catalogue
+// already records it or has no such weakness.
+func (c *cweCatalog) relate(id int, r RelatedWeakness) {
+	w, ok := c.Weaknesses[id]
+	if !ok {
+		return
+	}
+	for _, existing := range w.RelatedWeaknesses.RelatedWeakness {
+		if existing.Nature == r.Nature && existing.CWEID == r.CWEID && existing.ViewID == r.ViewID {
+			return
+		}
+	}
+	w.RelatedWeaknesses.RelatedWeakness = append(w.RelatedWeaknesses.RelatedWeakness, r)
+	c.Weaknesses[id] = w
+}
+
+// name is the name of the weakness or category of an ID, empty when the
+// catalogue has neither.
+func (c *cweCatalog) name(id int) string {
+	if w, ok := c.Weaknesses[id]; ok {
+		return w.Name
+	}
+	return c.Categories[id].Name
+}
+
+// Weakness returns the weakness of a CWE ID such as CWE-416, os.ErrNotExist
+// when the catalogue doesn't have it.
+func (c *cweCatalog) Weakness(id string) (WeaknessType, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(id, "CWE-"))
+	if err != nil {
+		return WeaknessType{}, fmt.Errorf("invalid CWE ID %q", id)
+	}
+	w, ok := c.Weaknesses[n]
+	if !ok {
+		return WeaknessType{}, os.ErrNotExist
+	}
+	return w, nil
+}
+
+// loadWeaknesses loads every weakness of the cwe input of a source as documents
+// of ID CWE-<ID>, from the catalogue when the input is an XML file and from the
+// JSON files of vuln-list/cwe otherwise. Files that fail to parse are recorded
+// and left out.
+func loadWeaknesses(ctx *source.Context, path string) ([]source.Document, error) {
+	if isCWECatalog(path) {
+		c, err := loadCWECatalog(path)
+		if err != nil {
+			return nil, err
+		}
+		var docs []source.Document
+		for _, w := range c.Weaknesses {
+			docs = append(docs, source.Document{ID: fmt.Sprintf("CWE-%d", w.ID), Path: path, Data: w})
+		}
+		sort.Slice(docs, func(i, j int) bool {
+			return docs[i].Data.(WeaknessType).ID < docs[j].Data.(WeaknessType).ID
+		})
+		return docs, nil
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(path, "CWE-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var docs []source.Document
+	for _, file := range files {
+		w, err := loadWeakness(file)
+		if err != nil {
+			ctx.RecordError(file, report.StageParse, err)
+			continue
+		}
+		docs = append(docs, source.Document{ID: fmt.Sprintf("CWE-%d", w.ID), Path: file, Data: w})
+	}
+	return docs, nil
+}
+
+// UnmarshalXML reads the XHTML of the structured text of the CWE catalogue as
+// paragraphs. Block elements start a paragraph, a list is a paragraph of
+// Markdown list items and preformatted text is a fenced code block.
+func (s *StructuredTextType) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	var paragraphs []string
+	var text, code strings.Builder
+	inCode, inList := 0, 0
+	// listed is whether the last paragraph is the items of the open list
+	listed := false
+	flush := func() {
+		p := strings.Join(strings.Fields(text.String()), " ")
+		text.Reset()
+		switch {
+		case p == "":
+		case inList > 0 && listed && strings.HasPrefix(p, "- "):
+			paragraphs[len(paragraphs)-1] += "\n" + p
+		default:
+			paragraphs = append(paragraphs, p)
+			listed = inList > 0 && strings.HasPrefix(p, "- ")
+		}
+	}
+
+	for depth := 0; ; {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch name := t.Name.Local; {
+			case name == "pre":
+				if inCode == 0 {
+					flush()
+					code.Reset()
+				}
+				inCode++
+			case name == "br" && inCode > 0:
+				code.WriteString("\n")
+			case inCode > 0:
+			case name == "li":
+				flush()
+				text.WriteString("- ")
+			case name == "code":
+				text.WriteString("`")
+			case name == "ul" || name == "ol":
+				flush()
+				inList++
+				listed = false
+			case name == "p" || name == "div" || name == "br" || name == "table" || name == "tr":
+				flush()
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				flush()
+				*s = paragraphs
+				return nil
+			}
+			depth--
+			switch name := t.Name.Local; {
+			case name == "pre":
+				if inCode--; inCode == 0 {
+					paragraphs = append(paragraphs, "```\n"+strings.Trim(code.String(), "\n")+"\n```")
+					listed = false
+				}
+			case inCode > 0:
+			case name == "code":
+				text.WriteString("`")
+			case name == "ul" || name == "ol":
+				flush()
+				inList--
+				listed = false
+			case name == "p" || name == "div" || name == "li" || name == "td" || name == "tr":
+				flush()
+			}
+		case xml.CharData:
+			if inCode > 0 {
+				code.Write(t)
+			} else {
+				text.Write(t)
+			}
+		}
+	}
+}
+
+// RelationView is the relations of a weakness in a view of the CWE catalogue.
+type RelationView struct {
+	ViewID    int
+	Name      string
+	Relations []RelatedWeakness
+}
+
+// Title is the view's name and ID, only its ID when it wasn't resolved from the catalogue.
+func (v RelationView) Title() string {
+	if v.Name == "" {
+		return fmt.Sprintf("CWE-%d", v.ViewID)
+	}
+	return fmt.Sprintf("%s (CWE-%d)", v.Name, v.ViewID)
+}
+
+// natureOrder orders the relations of a view: parents, children, then categories.
+var natureOrder = map[NatureEnumeration]int{"ChildOf": 0, "ParentOf": 1, "MemberOf": 2}
+
+// Hierarchy groups the relations of a weakness by view, in the order of their
+// IDs. In a view, parents come first, then children and categories, each by ID.
+func (w WeaknessType) Hierarchy() []RelationView {
+	var views []RelationView
+	index := make(map[int]int)
+	for _, r := range w.RelatedWeaknesses.RelatedWeakness {
+		i, ok := index[r.ViewID]
+		if !ok {
+			i = len(views)
+			index[r.ViewID] = i
+			views = append(views, RelationView{ViewID: r.ViewID, Name: r.ViewName})
+		}
+		views[i].Relations = append(views[i].Relations, r)
+	}
+
+	sort.Slice(views, func(i, j int) bool { return views[i].ViewID < views[j].ViewID })
+	for _, v := range views {
+		sort.SliceStable(v.Relations, func(i, j int) bool {
+			a, b := v.Relations[i], v.Relations[j]
+			oa, ok := natureOrder[a.Nature]
+			if !ok {
+				oa = len(natureOrder)
+			}
+			ob, ok := natureOrder[b.Nature]
+			if !ok {
+				ob = len(natureOrder)
+			}
+			if oa != ob {
+				return oa < ob
+			}
+			return a.CWEID < b.CWEID
+		})
+	}
+	return views
+}
+
+// Markdown lists the related entry with the nature of the relation. Weaknesses
+// of the catalogue link to their page, categories and entries missing from it
+// to cwe.mitre.org.
+func (r RelatedWeakness) Markdown() string {
+	mitre := fmt.Sprintf("https://cwe.mitre.org/data/definitions/%d.html", r.CWEID)
+	switch {
+	case r.Name == "":
+		return fmt.Sprintf("- %s [CWE-%d](%s)", r.Nature, r.CWEID, mitre)
+	case r.Nature == "MemberOf":
+		return fmt.Sprintf("- %s [CWE-%d: %s](%s)", r.Nature, r.CWEID, r.Name, mitre)
+	default:
+		return fmt.Sprintf("- %s [CWE-%d: %s](/cwe/cwe-%d)", r.Nature, r.CWEID, r.Name, r.CWEID)
+	}
+}
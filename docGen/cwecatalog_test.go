@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/avd-generator/manifest"
+	"github.com/aquasecurity/avd-generator/source"
+)
+
+func TestLoadCWECatalog(t *testing.T) {
+	c, err := loadCWECatalog("../goldens/cwec/cwec.xml")
+	require.NoError(t, err)
+	assert.Equal(t, "4.14", c.Version)
+	// CWE-71 is deprecated
+	assert.Len(t, c.Weaknesses, 4)
+	assert.NotContains(t, c.Weaknesses, 71)
+	assert.Equal(t, "Memory Buffer Errors", c.Categories[1218].Name)
+	assert.Equal(t, []CWEMember{{CWEID: 1218, ViewID: 699}, {CWEID: 399, ViewID: 699}}, c.Views[699].Members.HasMember)
+
+	w, err := c.Weakness("CWE-416")
+	require.NoError(t, err)
+	assert.Equal(t, "Use After Free", w.Name)
+	assert.Equal(t, "Referencing memory after it has been freed can cause a program to crash, use unexpected values, or execute code.", w.Description)
+	assert.Equal(t, StructuredTextType{
+		"The use of previously-freed memory can have any number of adverse consequences, ranging from the corruption of valid data to the execution of arbitrary code, depending on the instantiation and timing of the flaw.",
+		"Use-after-free errors have two common and sometimes overlapping causes:",
+		"- Error conditions and other exceptional circumstances.\n- Confusion over which part of the program is responsible for freeing the memory.",
+	}, w.ExtendedDescription)
+	assert.Equal(t, Mitigation{
+		Phase:    []PhaseEnumeration{"Implementation"},
+		Strategy: "Attack Surface Reduction",
+		Description: StructuredTextType{
+			"When freeing pointers, be sure to set them to NULL once they are freed:",
+			"```\nfree(ptr);\nptr = NULL;\n```",
+			"The utilization of multiple or complex data structures may lower the usefulness of this strategy.",
+		},
+	}, w.PotentialMitigations.Mitigation[1])
+	assert.Equal(t, []RelatedAttackPattern{{CAPECID: 7}}, w.RelatedAttackPatterns.RelatedAttackPattern)
+	assert.Equal(t, []RelatedWeakness{
+		{Nature: "ChildOf", CWEID: 825, ViewID: 1000, Name: "Expired Pointer Dereference", ViewName: "Research Concepts"},
+		{Nature: "ChildOf", CWEID: 672, ViewID: 1000, ViewName: "Research Concepts"},
+		{Nature: "ChildOf", CWEID: 825, ViewID: 1003, Name: "Expired Pointer Dereference"},
+		{Nature: "MemberOf", CWEID: 399, ViewID: 699, Name: "Resource Management Errors", ViewName: "Software Development"},
+	}, w.RelatedWeaknesses.RelatedWeakness)
+
+	// the catalogue only has ChildOf, so parents are found from their children
+	assert.ElementsMatch(t, []RelatedWeakness{
+		{Nature: "ParentOf", CWEID: 787, ViewID: 1000, Name: "Out-of-bounds Write", ViewName: "Research Concepts"},
+		{Nature: "ParentOf", CWEID: 825, ViewID: 1000, Name: "Expired Pointer Dereference", ViewName: "Research Concepts"},
+		{Nature: "MemberOf", CWEID: 1218, ViewID: 699, Name: "Memory Buffer Errors", ViewName: "Software Development"},
+	}, c.Weaknesses[119].RelatedWeaknesses.RelatedWeakness)
+
+	_, err = c.Weakness("CWE-71")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestStructuredTextUnmarshalXML(t *testing.T) {
+	var s struct {
+		Description StructuredTextType
+	}
+	require.NoError(t, xml.Unmarshal([]byte(`<Weakness xmlns:xhtml="http://www.w3.org/1999/xhtml"><Description>
+		Plain text before
+		the first paragraph.
+		<xhtml:p>Call <xhtml:code>free</xhtml:code> once.</xhtml:p>
+		<xhtml:div>Example Language: C<xhtml:br/>Code:</xhtml:div>
+		<xhtml:pre>if (p) {<xhtml:br/>    free(p);<xhtml:br/>}</xhtml:pre>
+		<xhtml:ol><xhtml:li>First</xhtml:li><xhtml:li>Second</xhtml:li></xhtml:ol>
+	</Description></Weakness>`), &s))
+	assert.Equal(t, StructuredTextType{
+		"Plain text before the first paragraph.",
+		"Call `free` once.",
+		"Example Language: C",
+		"Code:",
+		"```\nif (p) {\n    free(p);\n}\n```",
+		"- First\n- Second",
+	}, s.Description)
+
+	m := Mitigation{Strategy: "Refactoring", Description: s.Description[3:5]}
+	assert.Equal(t, "- **Refactoring**: Code:\n\n  ```\n  if (p) {\n      free(p);\n  }\n  ```", m.Markdown())
+}
+
+func TestWeaknessHierarchy(t *testing.T) {
+	c, err := loadCWECatalog("../goldens/cwec/cwec.xml")
+	require.NoError(t, err)
+	views := c.Weaknesses[416].Hierarchy()
+	require.Len(t, views, 3)
+	assert.Equal(t, "Software Development (CWE-699)", views[0].Title())
+	assert.Equal(t, "CWE-1003", views[2].Title())
+
+	var relations []string
+	for _, r := range views[1].Relations {
+		relations = append(relations, r.Markdown())
+	}
+	// CWE-672 isn't in the catalogue
+	assert.Equal(t, []string{
+		"- ChildOf [CWE-672](https://cwe.mitre.org/data/definitions/672.html)",
+		"- ChildOf [CWE-825: Expired Pointer Dereference](/cwe/cwe-825)",
+	}, relations)
+	assert.Equal(t, "- MemberOf [CWE-399: Resource Management Errors](https://cwe.mitre.org/data/definitions/399.html)", views[0].Relations[0].Markdown())
+
+	assert.Empty(t, WeaknessType{}.Hierarchy())
+}
+
+func TestGenerateCWEPagesFromCatalog(t *testing.T) {
+	nvdDir := t.TempDir()
+	b, err := os.ReadFile("../goldens/json/nvd/CVE-2020-0002.json")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(nvdDir, "CVE-2020-0002.json"), b, 0600))
+
+	pagesDir := t.TempDir()
+	result := source.Run(newTestContext(map[string]string{"cwe": "../goldens/cwec/cwec.xml", "nvd": nvdDir}, pagesDir, nil), cweSource{})
+	require.NoError(t, result.Err)
+	assert.Equal(t, 4, result.Documents)
+
+	got, err := os.ReadFile(filepath.Join(pagesDir, "CWE-119.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(got), `
+### Relationships
+
+#### Software Development (CWE-699)
+- MemberOf [CWE-1218: Memory Buffer Errors](https://cwe.mitre.org/data/definitions/1218.html)
+
+#### Research Concepts (CWE-1000)
+- ParentOf [CWE-787: Out-of-bounds Write](/cwe/cwe-787)
+- ParentOf [CWE-825: Expired Pointer Dereference](/cwe/cwe-825)
+
+### Common Consequences
+`)
+
+	got, err = os.ReadFile(filepath.Join(pagesDir, "CWE-416.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(got), `
+#### Implementation
+- **Attack Surface Reduction**: When freeing pointers, be sure to set them to NULL once they are freed:
+
+  `+"```"+`
+  free(ptr);
+  ptr = NULL;
+  `+"```"+`
+
+  The utilization of multiple or complex data structures may lower the usefulness of this strategy.
+`)
+	assert.Contains(t, string(got), "| [CVE-2020-0002](/nvd/cve-2020-0002) | 2020-01-08 |")
+
+	_, err = os.Stat(filepath.Join(pagesDir, "CWE-71.md"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateVulnerabilityPageFromCWECatalog(t *testing.T) {
+	c, err := loadCWECatalog("../goldens/cwec/cwec.xml")
+	require.NoError(t, err)
+	postsDir := t.TempDir()
+	m := manifest.New(nvdManifestVersion(""))
+	g := &vulnGenerator{ctx: newTestContext(nil, postsDir, nil), manifest: m, cweDir: "../goldens/cwec/cwec.xml", cweCatalog: c, cweCatalogHash: "v4.14", postsDir: postsDir}
+
+	file := filepath.Join("../goldens/json/nvd", "CVE-2020-0002.json")
+	require.Equal(t, pageWritten, g.generatePage(file, postsDir))
+	b, err := os.ReadFile(filepath.Join(postsDir, "CVE-2020-0002.md"))
+	require.NoError(t, err)
+	page := string(b)
+	assert.Contains(t, page, "\nshortName: \"Use After Free\"\n")
+	assert.Contains(t, page, "\n[CWE-416: Use After Free](/cwe/cwe-416)\n")
+	assert.Contains(t, page, "\n[CWE-787: Out-of-bounds Write](/cwe/cwe-787)\n")
+
+	// the catalogue is hashed once rather than for every page
+	e, ok := m.Get("CVE-2020-0002")
+	require.True(t, ok)
+	assert.Empty(t, e.Inputs)
+	assert.Equal(t, pageSkipped, g.generatePage(file, postsDir))
+	g.cweCatalogHash = "v4.15"
+	assert.Equal(t, pageUnchanged, g.generatePage(file, postsDir))
+}
+
+func TestGenerateCWEPagesWithoutCatalog(t *testing.T) {
+	pagesDir := t.TempDir()
+	result := source.Run(newTestContext(map[string]string{"cwe": "../goldens/cwec/missing.xml", "nvd": t.TempDir()}, pagesDir, nil), cweSource{})
+	require.NoError(t, result.Err)
+	assert.Equal(t, 0, result.Documents)
+
+	entries, err := os.ReadDir(pagesDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRenderVulnerabilityPagesWithoutCWECatalog(t *testing.T) {
+	nvdDir := t.TempDir()
+	b, err := os.ReadFile("../goldens/json/nvd/CVE-2020-0002.json")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(nvdDir, "CVE-2020-0002.json"), b, 0600))
+
+	postsDir := t.TempDir()
+	ctx := newTestContext(map[string]string{"cwe": "../goldens/cwec/missing.xml"}, postsDir, nil)
+	require.NoError(t, nvdSource{clock: fakeClock{}}.Render(ctx, []source.Document{{ID: "2020", Path: nvdDir}}))
+
+	b, err = os.ReadFile(filepath.Join(postsDir, "2020", "CVE-2020-0002.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "\n[CWE-416](https://cwe.mitre.org/data/definitions/416.html)\n")
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// debianEntry is what the Debian security tracker knows of a CVE in one
+// source package, vuln-list stores it at debian/<package>/<CVE ID>.json.
+type debianEntry struct {
+	Description string                   `json:"description"`
+	Releases    map[string]debianRelease `json:"releases"`
+}
+
+type debianRelease struct {
+	Status       string `json:"status"`
+	FixedVersion string `json:"fixed_version"`
+	Urgency      string `json:"urgency"`
+	NoDSA        string `json:"nodsa"`
+	NoDSAReason  string `json:"nodsa_reason"`
+}
+
+// debianUrgencies orders the urgencies the tracker assigns, the highest one of
+// every release is the Debian severity of a CVE.
+var debianUrgencies = []string{"unimportant", "low", "medium", "high"}
+
+// urgency is the release's urgency without the marks the tracker adds to
+// those it hasn't assigned itself, such as "low*".
+func (r debianRelease) urgency() string {
+	return strings.TrimRight(r.Urgency, "*")
+}
+
+// notes summarises the urgency of a release and why the security team won't
+// issue an advisory for it, such as "urgency low, no-dsa (ignored): Minor issue".
+func (r debianRelease) notes() string {
+	var notes []string
+	if u := r.urgency(); u != "" && u != "not yet assigned" {
+		notes = append(notes, "urgency "+u)
+	}
+	if r.NoDSA != "" {
+		note := "no-dsa"
+		if r.NoDSAReason != "" {
+			note += fmt.Sprintf(" (%s)", r.NoDSAReason)
+		}
+		notes = append(notes, note+": "+r.NoDSA)
+	}
+	return strings.Join(notes, ", ")
+}
+
+// fixed is whether the release has a fixed version, "0" meaning it never was vulnerable.
+func (r debianRelease) fixed() bool {
+	return r.Status == "resolved" && r.FixedVersion != "" && r.FixedVersion != "0"
+}
+
+// debianAdapter reads the Debian security tracker data, which is stored by
+// package rather than by CVE.
+type debianAdapter struct {
+	index *vendorIndex
+}
+
+func init() {
+	registerVendorAdapter("debian", newDebianAdapter)
+}
+
+func newDebianAdapter(dir string) VendorAdapter {
+	return debianAdapter{index: &vendorIndex{dir: dir, build: buildDebianIndex}}
+}
+
+func buildDebianIndex(dir string, _ func(file string, err error)) (map[string][]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "CVE-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string][]string)
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), ".json")
+		index[id] = append(index[id], file)
+	}
+	return index, nil
+}
+
+func (debianAdapter) Name() string { return "debian" }
+
+func (v debianAdapter) Locate(cveID, _ string) ([]string, error) { return v.index.Files(cveID) }
+
+func (v debianAdapter) CVEs(year string) ([]string, error) { return v.index.CVEs(year) }
+
+func (v debianAdapter) Load(cveID, _ string) (VendorInfo, error) {
+	files, err := v.index.Files(cveID)
+	if err != nil {
+		return VendorInfo{}, err
+	}
+	packages, err := loadDebianPackages(files)
+	if err != nil {
+		return VendorInfo{}, err
+	}
+	return VendorInfo{
+		Name:             v.Name(),
+		Description:      packages[0].Entry.Description,
+		Severity:         debianSeverity(packages),
+		AffectedSoftware: debianAffectedSoftware(packages),
+	}, nil
+}
+
+type debianPackage struct {
+	Name  string
+	Entry debianEntry
+}
+
+// loadDebianPackages reads the tracker data of a CVE for every package it
+// affects, one file each.
+func loadDebianPackages(files []string) ([]debianPackage, error) {
+	if len(files) == 0 {
+		return nil, os.ErrNotExist
+	}
+
+	var packages []debianPackage
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var e debianEntry
+		if err := json.Unmarshal(b, &e); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		packages = append(packages, debianPackage{Name: filepath.Base(filepath.Dir(file)), Entry: e})
+	}
+	return packages, nil
+}
+
+// debianSeverity is the highest urgency of any release of any package.
+func debianSeverity(packages []debianPackage) string {
+	severity := -1
+	for _, p := range packages {
+		for _, r := range p.Entry.Releases {
+			for i, u := range debianUrgencies {
+				if r.urgency() == u && i > severity {
+					severity = i
+				}
+			}
+		}
+	}
+	if severity < 0 {
+		return ""
+	}
+	return debianUrgencies[severity]
+}
+
+// debianAffectedSoftware lists the status of every release of every package,
+// leaving out the releases that never were vulnerable.
+func debianAffectedSoftware(packages []debianPackage) []AffectedSoftware {
+	var affected []AffectedSoftware
+	for _, p := range packages {
+		releases := make([]string, 0, len(p.Entry.Releases))
+		for name := range p.Entry.Releases {
+			releases = append(releases, name)
+		}
+		sort.Strings(releases)
+
+		for _, name := range releases {
+			r := p.Entry.Releases[name]
+			if r.Status == "resolved" && !r.fixed() {
+				continue
+			}
+			as := AffectedSoftware{
+				Name:         p.Name,
+				Vendor:       fmt.Sprintf("Debian/%s", name),
+				StartVersion: "*",
+				EndVersion:   "*",
+				Status:       r.Status,
+				Notes:        r.notes(),
+			}
+			if r.fixed() {
+				as.EndVersion = r.FixedVersion + " (excluding)"
+			}
+			affected = append(affected, as)
+		}
+	}
+	return affected
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebianAdapter(t *testing.T) {
+	adapter := newDebianAdapter("../goldens/json/debian")
+	files, err := adapter.Locate("CVE-2020-0002", "2020")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"../goldens/json/debian/android-platform-external-libavc/CVE-2020-0002.json",
+		"../goldens/json/debian/tar/CVE-2020-0002.json",
+	}, files)
+
+	info, err := adapter.Load("CVE-2020-0002", "2020")
+	require.NoError(t, err)
+	assert.Equal(t, "medium", info.Severity)
+	assert.Equal(t, "In ih264d_init_decoder of ih264d_api.c, there is a possible out of bounds write due to a use after free.", info.Description)
+	// bookworm never was vulnerable
+	assert.Equal(t, []AffectedSoftware{
+		{Name: "android-platform-external-libavc", Vendor: "Debian/bullseye", StartVersion: "*", EndVersion: "*", Status: "open", Notes: "urgency low, no-dsa (ignored): Minor issue"},
+		{Name: "android-platform-external-libavc", Vendor: "Debian/sid", StartVersion: "*", EndVersion: "10.0.0+r36-1 (excluding)", Status: "resolved", Notes: "urgency medium"},
+		{Name: "tar", Vendor: "Debian/buster", StartVersion: "*", EndVersion: "1.30+dfsg-6 (excluding)", Status: "resolved", Notes: "urgency unimportant"},
+	}, info.AffectedSoftware)
+
+	_, err = adapter.Load("CVE-2020-1234", "2020")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestGenerateVulnerabilityPageDebian(t *testing.T) {
+	nvdDir := "../goldens/json/nvd"
+	postsDir := t.TempDir()
+	g := &vulnGenerator{ctx: newTestContext(nil, postsDir, nil), cweDir: "../goldens/cwe", postsDir: postsDir,
+		vendors: append(testVendors(), newDebianAdapter("../goldens/json/debian"))}
+
+	require.Equal(t, pageWritten, g.generatePage(filepath.Join(nvdDir, "CVE-2020-0002.json"), postsDir))
+	b, err := os.ReadFile(filepath.Join(postsDir, "CVE-2020-0002.md"))
+	require.NoError(t, err)
+	page := string(b)
+	assert.Contains(t, page, "\ndebian_severity: \"MEDIUM\"\n")
+	assert.Contains(t, page, `
+### Affected Software {.with_icon .affected_software}
+| Name | Vendor           | Start Version | End Version | Status | Notes |
+| ------------- |-------------|-----|----|----|----|
+| Android | Google | 8.0 (including) | 8.0 (including) |  |  |
+`)
+	assert.Contains(t, page, `
+| Chromium-browser | RedHat/Red Hat Enterprise Linux 6 Supplementary | * | 80.0.3987.87-1.el6_10 (excluding) | fixed | [RHSA-2020:0514](https://access.redhat.com/errata/RHSA-2020:0514) |
+| Android-platform-external-libavc | Debian/bullseye | * | * | open | urgency low, no-dsa (ignored): Minor issue |
+| Android-platform-external-libavc | Debian/sid | * | 10.0.0+r36-1 (excluding) | resolved | urgency medium |
+| Tar | Debian/buster | * | 1.30+dfsg-6 (excluding) | resolved | urgency unimportant |
+`)
+}
+
+func TestGenerateReservedPagesDebian(t *testing.T) {
+	postsDir := t.TempDir()
+	inputDir := "../goldens/reserved-no-existing-info"
+	generateReservedPages(newTestContext(nil, postsDir, nil), "2020", fakeClock{}, map[string]bool{"CVE-2020-11932": true}, reservedTestVendors(inputDir), postsDir)
+
+	b, err := os.ReadFile(filepath.Join(postsDir, "CVE-2020-0570.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `
+### Debian
+Qt through 5.14 has an uncontrolled search path for plugins.
+
+
+
+#### Affected Software List
+| Name | Vendor           | Start Version | End Version | Status | Notes |
+| ------------- |-------------|-----|----|----|----|
+| Qtbase-opensource-src | Debian/bullseye | * | 5.12.5+dfsg-7 (excluding) | resolved | urgency low |
+| Qtbase-opensource-src | Debian/buster | * | * | open | urgency low, no-dsa: Minor issue |
+`)
+
+	// NVD already has CVE-2020-11932
+	_, err = os.Stat(filepath.Join(postsDir, "CVE-2020-11932.md"))
+	assert.True(t, os.IsNotExist(err))
+}
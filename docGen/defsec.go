@@ -12,6 +12,8 @@ import (
 	"text/template"
 
 	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/aquasecurity/avd-generator/util"
 	"github.com/aquasecurity/trivy/pkg/iac/framework"
 	_ "github.com/aquasecurity/trivy/pkg/iac/rego"
@@ -57,9 +59,17 @@ func init() {
 	}
 }
 
-func generateDefsecComplianceSpecPages(specDir, contentDir string) {
+func init() {
+	source.Register("compliance-spec", func() source.Source { return complianceSpecSource{} })
+	source.Register("defsec", func() source.Source { return defsecSource{} })
+}
+
+type complianceSpecSource struct{}
 
-	if err := filepath.Walk(specDir, func(path string, info fs.FileInfo, err error) error {
+func (complianceSpecSource) Load(ctx *source.Context) ([]source.Document, error) {
+	var docs []source.Document
+
+	if err := filepath.Walk(ctx.Input("specs"), func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -81,19 +91,33 @@ func generateDefsecComplianceSpecPages(specDir, contentDir string) {
 		if spec.Spec.Category == "" {
 			spec.Spec.Category = "kubernetes"
 		}
+
+		docs = append(docs, source.Document{ID: spec.Spec.ID, Path: path, Data: spec})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (complianceSpecSource) Render(ctx *source.Context, docs []source.Document) error {
+	contentDir := ctx.Output()
+
+	for _, doc := range docs {
+		spec := doc.Data.(DefsecComplianceSpec)
+
 		outputDir := filepath.Join(contentDir, spec.Spec.Category)
 		title := fmt.Sprintf("%s-%s", strings.ToUpper(spec.Spec.Title), spec.Spec.Version)
-		complianceMenu.AddNode(title, fmt.Sprintf("%s-%s", spec.Spec.Title, spec.Spec.Version), filepath.Join(outputDir),
+		ctx.Menu.AddNode(title, fmt.Sprintf("%s-%s", spec.Spec.Title, spec.Spec.Version), filepath.Join(outputDir),
 			spec.Spec.Category, []string{},
 			[]menu.BreadCrumb{{Name: "Compliance", Url: "/compliance"},
 				{Name: strings.Title(spec.Spec.Category), Url: fmt.Sprintf("/compliance/%s", spec.Spec.Category)}}, spec.Spec.Category, true)
 
-		return generateDefsecComplianceSpecPage(spec, contentDir)
-
-	}); err != nil {
-		fmt.Println(err)
+		if err := generateDefsecComplianceSpecPage(spec, contentDir); err != nil {
+			ctx.RecordError(doc.Path, report.StageWrite, err)
+		}
 	}
-
+	return nil
 }
 
 func getSummary(id string) string {
@@ -139,16 +163,31 @@ func generateDefsecComplianceSpecPage(spec DefsecComplianceSpec, contentDir stri
 	return nil
 }
 
-func generateDefsecPages(remediationDir, contentDir string) {
+type defsecSource struct{}
+
+func (defsecSource) Load(ctx *source.Context) ([]source.Document, error) {
+	var docs []source.Document
 	for _, r := range rules.GetRegistered(framework.ALL) {
+		rule := r.GetRule()
+		docs = append(docs, source.Document{ID: rule.AVDID, Data: rule})
+	}
+	return docs, nil
+}
+
+func (defsecSource) Render(ctx *source.Context, docs []source.Document) error {
+	docsDir := ctx.Input("docs")
+	contentDir := ctx.Output()
 
-		avdId := r.GetRule().AVDID
-		topLevelID := strings.ToLower(r.GetRule().Provider.ConstName())
-		branchID := r.GetRule().Service
+	for _, doc := range docs {
+		rule := doc.Data.(scan.Rule)
+
+		avdId := rule.AVDID
+		topLevelID := strings.ToLower(rule.Provider.ConstName())
+		branchID := rule.Service
 		branchID = util.RemapCategory(branchID)
 
 		log.Printf("Getting remediation markdown for %s", avdId)
-		remediationDir := filepath.Join(remediationDir, strings.ToLower(r.GetRule().Provider.ConstName()), strings.ReplaceAll(r.GetRule().Service, "-", ""), avdId)
+		remediationDir := filepath.Join(docsDir, strings.ToLower(rule.Provider.ConstName()), strings.ReplaceAll(rule.Service, "-", ""), avdId)
 
 		remediations := make(map[string]string)
 		docsFile := filepath.Join(remediationDir, "docs.md")
@@ -172,7 +211,7 @@ func generateDefsecPages(remediationDir, contentDir string) {
 
 			return nil
 		}); err != nil {
-			fmt.Println(err.Error())
+			ctx.RecordError(remediationDir, report.StageRead, err)
 			continue
 		}
 
@@ -185,14 +224,14 @@ func generateDefsecPages(remediationDir, contentDir string) {
 			}
 		}
 
-		if err := generateDefsecCheckPage(r.GetRule(), remediations, contentDir, docsFile, branchID); err != nil {
-			log.Printf("an error occurred writing the page for %s. %v", r.GetRule().AVDID, err)
+		if err := generateDefsecCheckPage(rule, remediations, contentDir, docsFile, branchID); err != nil {
+			ctx.RecordError(docsFile, report.StageWrite, err)
 		}
 
-		providerName := r.GetRule().Provider.DisplayName()
-		misConfigurationMenu.AddNode(topLevelID, providerName, contentDir, "", []string{},
+		providerName := rule.Provider.DisplayName()
+		ctx.Menu.AddNode(topLevelID, providerName, contentDir, "", []string{},
 			[]menu.BreadCrumb{}, topLevelID, true)
-		misConfigurationMenu.AddNode(branchID, branchID, filepath.Join(contentDir, topLevelID),
+		ctx.Menu.AddNode(branchID, branchID, filepath.Join(contentDir, topLevelID),
 			topLevelID, []string{},
 			[]menu.BreadCrumb{
 				{
@@ -200,6 +239,7 @@ func generateDefsecPages(remediationDir, contentDir string) {
 				},
 			}, topLevelID, false)
 	}
+	return nil
 }
 
 func generateDefsecCheckPage(rule scan.Rule, remediations map[string]string, contentDir string, docsFile string, menuParent string) error {
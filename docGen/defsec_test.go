@@ -5,6 +5,8 @@ import (
 	"os"
 	"testing"
 
+	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -13,7 +15,8 @@ func TestLoadsAsExpected(t *testing.T) {
 
 	tempDir := t.TempDir()
 
-	generateDefsecPages("../goldens/defsec/md", tempDir)
+	result := source.Run(newTestContext(map[string]string{"docs": "../goldens/defsec/md"}, tempDir, menu.New("misconfig", tempDir)), defsecSource{})
+	require.NoError(t, result.Err)
 
 	ids := []string{"avd-aws-0018"}
 
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EPSS is the Exploit Prediction Scoring System score of a CVE, the
+// probability of it being exploited in the next 30 days, and its percentile
+// among every scored CVE.
+type EPSS struct {
+	Score      float64
+	Percentile float64
+	// Date is the day of the snapshot the score comes from.
+	Date string
+}
+
+type epssSnapshot struct {
+	Date   string
+	Model  string
+	Scores map[string]EPSS
+}
+
+var snapshotDateRegex = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+// loadEPSSSnapshot reads a CSV snapshot as published at
+// https://www.first.org/epss/data_stats. The snapshot date comes from its
+// "#model_version:...,score_date:..." header, or from the file name or
+// modification time of snapshots without one.
+func loadEPSSSnapshot(path string) (*epssSnapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	snapshot := &epssSnapshot{Scores: make(map[string]EPSS)}
+	r := bufio.NewReader(f)
+	headerLines := 0
+	if b, err := r.Peek(1); err == nil && b[0] == '#' {
+		headerLines = 1
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range strings.Split(strings.TrimSpace(strings.TrimPrefix(header, "#")), ",") {
+			key, value, _ := strings.Cut(field, ":")
+			switch key {
+			case "model_version":
+				snapshot.Model = value
+			case "score_date":
+				snapshot.Date = snapshotDateRegex.FindString(value)
+			}
+		}
+	}
+	if snapshot.Date == "" {
+		snapshot.Date = snapshotDateRegex.FindString(path)
+	}
+	if snapshot.Date == "" {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Date = info.ModTime().UTC().Format("2006-01-02")
+	}
+
+	cr := csv.NewReader(r)
+	columns, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+	cveCol, scoreCol, percentileCol := -1, -1, -1
+	for i, c := range columns {
+		switch strings.TrimSpace(c) {
+		case "cve":
+			cveCol = i
+		case "epss":
+			scoreCol = i
+		case "percentile":
+			percentileCol = i
+		}
+	}
+	if cveCol < 0 || scoreCol < 0 || percentileCol < 0 {
+		return nil, fmt.Errorf("header %q lacks a cve, epss or percentile column", strings.Join(columns, ","))
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		line += headerLines
+		score, err := strconv.ParseFloat(record[scoreCol], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid score: %w", line, err)
+		}
+		percentile, err := strconv.ParseFloat(record[percentileCol], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid percentile: %w", line, err)
+		}
+		snapshot.Scores[record[cveCol]] = EPSS{Score: score, Percentile: percentile, Date: snapshot.Date}
+	}
+	return snapshot, nil
+}
+
+// age returns how old the snapshot is at now.
+func (s *epssSnapshot) age(now time.Time) (time.Duration, error) {
+	date, err := time.Parse("2006-01-02", s.Date)
+	if err != nil {
+		return 0, err
+	}
+	return now.Sub(date), nil
+}
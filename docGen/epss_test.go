@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/avd-generator/report"
+)
+
+func TestLoadEPSSSnapshot(t *testing.T) {
+	t.Run("with a header", func(t *testing.T) {
+		snapshot, err := loadEPSSSnapshot("../goldens/epss/epss_scores-current.csv")
+		require.NoError(t, err)
+		assert.Equal(t, "2024-05-02", snapshot.Date)
+		assert.Equal(t, "v2023.03.01", snapshot.Model)
+		assert.Len(t, snapshot.Scores, 4)
+		assert.Equal(t, EPSS{Score: 0.02741, Percentile: 0.90347, Date: "2024-05-02"}, snapshot.Scores["CVE-2020-0002"])
+	})
+
+	t.Run("dated by its file name", func(t *testing.T) {
+		snapshot, err := loadEPSSSnapshot("../goldens/epss/epss_scores-2024-04-30.csv")
+		require.NoError(t, err)
+		assert.Equal(t, "2024-04-30", snapshot.Date)
+		assert.Equal(t, EPSS{Score: 0.02741, Percentile: 0.90347, Date: "2024-04-30"}, snapshot.Scores["CVE-2020-0002"])
+	})
+
+	t.Run("invalid score", func(t *testing.T) {
+		_, err := loadEPSSSnapshot("../goldens/epss/epss_scores-invalid.csv")
+		assert.ErrorContains(t, err, "line 3: invalid score")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := loadEPSSSnapshot("../goldens/epss/missing.csv")
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestNVDLoadEPSS(t *testing.T) {
+	s := nvdSource{clock: fakeClock{}}
+	ctx := newTestContext(nil, t.TempDir(), nil)
+	ctx.Report = report.New()
+
+	// fakeClock is years after the snapshot, a stale snapshot is still used
+	ctx.Source.Options["epss-max-age"] = "7"
+	epss, err := s.loadEPSS(ctx, "../goldens/epss/epss_scores-current.csv")
+	require.NoError(t, err)
+	assert.Len(t, epss, 4)
+
+	epss, err = s.loadEPSS(ctx, "../goldens/epss/missing.csv")
+	require.NoError(t, err)
+	assert.Nil(t, epss)
+
+	epss, err = s.loadEPSS(ctx, "../goldens/epss/epss_scores-invalid.csv")
+	require.NoError(t, err)
+	assert.Nil(t, epss)
+	assert.Equal(t, 1, ctx.Report.Count("test", report.StageLoad))
+
+	ctx.Source.Options["epss-max-age"] = "a week"
+	_, err = s.loadEPSS(ctx, "../goldens/epss/epss_scores-current.csv")
+	assert.EqualError(t, err, `invalid epss-max-age "a week"`)
+}
+
+func TestGenerateVulnerabilityPageEPSS(t *testing.T) {
+	snapshot, err := loadEPSSSnapshot("../goldens/epss/epss_scores-current.csv")
+	require.NoError(t, err)
+
+	nvdDir := "../goldens/json/nvd"
+	postsDir := t.TempDir()
+	g := &vulnGenerator{ctx: newTestContext(nil, postsDir, nil), cweDir: "../goldens/cwe", postsDir: postsDir, epss: snapshot.Scores}
+
+	require.Equal(t, pageWritten, g.generatePage(filepath.Join(nvdDir, "CVE-2022-2788.json"), postsDir))
+	b, err := os.ReadFile(filepath.Join(postsDir, "CVE-2022-2788.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `
+
+epss_score: 0.00004
+epss_percentile: 0.00052
+epss_date: 2024-05-02
+
+---`)
+	assert.Contains(t, string(b), `
+### EPSS
+The [Exploit Prediction Scoring System](https://www.first.org/epss/) estimates the probability of this vulnerability being exploited in the next 30 days.
+
+| Score | Percentile | Snapshot Date |
+| ------------- |-------------|-----|
+| 0.00004 | 0.00052 | 2024-05-02 |
+`)
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 func getAllFiles(dir string) ([]string, error) {
@@ -40,3 +42,42 @@ func getAllFilesOfKind(dir string, include string, exclude string) ([]string, er
 	}
 	return filteredFiles, nil
 }
+
+// writeIfChanged writes b to path unless the file already holds exactly b, so
+// unchanged pages keep their modification time. It reports whether it wrote.
+func writeIfChanged(path string, b []byte) (bool, error) {
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, b) {
+		return false, nil
+	}
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// dirCache holds what was loaded from directories, so that the sources of a
+// run reading the same input load it, and record its broken files, once. It
+// is safe for concurrent use.
+type dirCache[T any] struct {
+	mu     sync.Mutex
+	loaded map[string]T
+}
+
+// get returns what was loaded from dir, calling load if nothing was yet.
+// Failed loads aren't kept, the next call tries again.
+func (c *dirCache[T]) get(dir string, load func(dir string) (T, error)) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.loaded[dir]; ok {
+		return v, nil
+	}
+	v, err := load(dir)
+	if err != nil {
+		return v, err
+	}
+	if c.loaded == nil {
+		c.loaded = make(map[string]T)
+	}
+	c.loaded[dir] = v
+	return v, nil
+}
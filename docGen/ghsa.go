@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/leekchan/gtf"
+
+	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
+)
+
+// ghsaFile is an advisory as vuln-list stores it, a file per affected package
+// at ghsa/<ecosystem>/<package>/<GHSA ID>.json.
+type ghsaFile struct {
+	Severity string
+	Package  struct {
+		Ecosystem string
+		Name      string
+	}
+	Advisory struct {
+		GhsaId     string
+		References []struct {
+			Url string
+		}
+		Identifiers []struct {
+			Type  string
+			Value string
+		}
+		Description string
+		PublishedAt string
+		Severity    string
+		Summary     string
+		UpdatedAt   string
+		WithdrawnAt string
+		CVSS        struct {
+			Score        float64
+			VectorString string
+		}
+	}
+	Versions []struct {
+		FirstPatchedVersion *struct {
+			Identifier string
+		}
+		VulnerableVersionRange string
+	}
+}
+
+// GHSAAdvisory is a GitHub Security Advisory with every package it affects.
+type GHSAAdvisory struct {
+	ID          string
+	Summary     string
+	Description string
+	Severity    string
+	Published   string
+	Updated     string
+	CVEIDs      []string
+	References  []string
+	CVSSVector  string
+	CVSSScore   float64
+	Packages    []GHSAPackage
+}
+
+type GHSAPackage struct {
+	Ecosystem string
+	Name      string
+	Ranges    []GHSAVersionRange
+}
+
+type GHSAVersionRange struct {
+	Vulnerable   string
+	FirstPatched string
+}
+
+// ecosystemNames are how the ecosystems GitHub advisories cover are known,
+// ecosystems missing from it are capitalised.
+var ecosystemNames = map[string]string{
+	"actions":  "GitHub Actions",
+	"composer": "Composer",
+	"erlang":   "Hex",
+	"go":       "Go",
+	"maven":    "Maven",
+	"npm":      "npm",
+	"nuget":    "NuGet",
+	"pip":      "PyPI",
+	"pub":      "Pub",
+	"rubygems": "RubyGems",
+	"rust":     "crates.io",
+	"swift":    "Swift",
+}
+
+// EcosystemID is the ecosystem as it appears in URLs.
+func (p GHSAPackage) EcosystemID() string {
+	return strings.ToLower(p.Ecosystem)
+}
+
+// EcosystemName is the ecosystem as it is shown on pages.
+func (p GHSAPackage) EcosystemName() string {
+	if name, ok := ecosystemNames[p.EcosystemID()]; ok {
+		return name
+	}
+	return capitalize(p.EcosystemID())
+}
+
+// URL is the advisory on github.com.
+func (a GHSAAdvisory) URL() string {
+	return fmt.Sprintf("https://github.com/advisories/%s", a.ID)
+}
+
+// ghsaAdvisories are the advisories loaded in this run, read by both the ghsa
+// and the nvd source.
+var ghsaAdvisories dirCache[map[string]*GHSAAdvisory]
+
+// sharedGHSAAdvisories returns the advisories under dir, loading them the
+// first time a source asks for them. They must not be modified.
+func sharedGHSAAdvisories(ctx *source.Context, dir string) (map[string]*GHSAAdvisory, error) {
+	return ghsaAdvisories.get(dir, func(dir string) (map[string]*GHSAAdvisory, error) {
+		return loadGHSAAdvisories(ctx, dir)
+	})
+}
+
+// loadGHSAAdvisories reads every advisory under dir, merging the files of the
+// packages an advisory affects. Withdrawn advisories are left out, files that
+// can't be read are recorded and skipped.
+func loadGHSAAdvisories(ctx *source.Context, dir string) (map[string]*GHSAAdvisory, error) {
+	files, err := getAllFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	advisories := make(map[string]*GHSAAdvisory)
+	for _, file := range files {
+		if filepath.Ext(file) != ".json" {
+			continue
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			ctx.RecordError(file, report.StageRead, err)
+			continue
+		}
+		var f ghsaFile
+		if err := json.Unmarshal(b, &f); err != nil {
+			ctx.RecordError(file, report.StageParse, err)
+			continue
+		}
+		if f.Advisory.GhsaId == "" {
+			ctx.RecordError(file, report.StageParse, fmt.Errorf("no GHSA ID"))
+			continue
+		}
+		if f.Advisory.WithdrawnAt != "" {
+			continue
+		}
+
+		a, ok := advisories[f.Advisory.GhsaId]
+		if !ok {
+			a = &GHSAAdvisory{
+				ID:          f.Advisory.GhsaId,
+				Summary:     f.Advisory.Summary,
+				Description: f.Advisory.Description,
+				Severity:    f.Advisory.Severity,
+				Published:   f.Advisory.PublishedAt,
+				Updated:     f.Advisory.UpdatedAt,
+				CVSSVector:  f.Advisory.CVSS.VectorString,
+				CVSSScore:   f.Advisory.CVSS.Score,
+			}
+			for _, id := range f.Advisory.Identifiers {
+				if id.Type == "CVE" {
+					a.CVEIDs = append(a.CVEIDs, id.Value)
+				}
+			}
+			for _, ref := range f.Advisory.References {
+				a.References = append(a.References, ref.Url)
+			}
+			advisories[a.ID] = a
+		}
+
+		pkg := GHSAPackage{Ecosystem: f.Package.Ecosystem, Name: f.Package.Name}
+		for _, v := range f.Versions {
+			r := GHSAVersionRange{Vulnerable: v.VulnerableVersionRange}
+			if v.FirstPatchedVersion != nil {
+				r.FirstPatched = v.FirstPatchedVersion.Identifier
+			}
+			pkg.Ranges = append(pkg.Ranges, r)
+		}
+		a.Packages = append(a.Packages, pkg)
+	}
+
+	for _, a := range advisories {
+		sort.Slice(a.Packages, func(i, j int) bool {
+			if a.Packages[i].Ecosystem != a.Packages[j].Ecosystem {
+				return a.Packages[i].Ecosystem < a.Packages[j].Ecosystem
+			}
+			return a.Packages[i].Name < a.Packages[j].Name
+		})
+	}
+	return advisories, nil
+}
+
+// ghsaByCVE indexes the advisories that have a CVE ID by it.
+func ghsaByCVE(advisories map[string]*GHSAAdvisory) map[string][]GHSAAdvisory {
+	byCVE := make(map[string][]GHSAAdvisory)
+	for _, a := range advisories {
+		for _, id := range a.CVEIDs {
+			byCVE[id] = append(byCVE[id], *a)
+		}
+	}
+	for _, advisories := range byCVE {
+		sort.Slice(advisories, func(i, j int) bool { return advisories[i].ID < advisories[j].ID })
+	}
+	return byCVE
+}
+
+func init() {
+	source.Register("ghsa", func() source.Source { return ghsaSource{} })
+}
+
+// ghsaSource renders the advisories that have no CVE ID, those that do are
+// shown on the pages of their CVEs.
+type ghsaSource struct{}
+
+func (ghsaSource) Load(ctx *source.Context) ([]source.Document, error) {
+	advisories, err := sharedGHSAAdvisories(ctx, ctx.Input("ghsa"))
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []source.Document
+	for id, a := range advisories {
+		if len(a.CVEIDs) > 0 || len(a.Packages) == 0 {
+			continue
+		}
+		docs = append(docs, source.Document{ID: id, Path: id, Data: *a})
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	return docs, nil
+}
+
+func (ghsaSource) Render(ctx *source.Context, docs []source.Document) error {
+	postsDir := ctx.Output()
+	log.Printf("generating GitHub advisory pages in: %s", postsDir)
+
+	for _, doc := range docs {
+		a := doc.Data.(GHSAAdvisory)
+		// an advisory is filed under the first ecosystem it affects
+		pkg := a.Packages[0]
+
+		pageFile := filepath.Join(postsDir, pkg.EcosystemID(), fmt.Sprintf("%s.md", a.ID))
+		if err := os.MkdirAll(filepath.Dir(pageFile), 0755); err != nil {
+			return err
+		}
+
+		var page bytes.Buffer
+		if err := GHSAPostToMarkdown(a, &page, GetCustomContentFromMarkdown(pageFile)); err != nil {
+			ctx.RecordError(doc.Path, report.StageRender, err)
+			continue
+		}
+		if _, err := writeIfChanged(pageFile, page.Bytes()); err != nil {
+			ctx.RecordError(doc.Path, report.StageWrite, err)
+			continue
+		}
+
+		ctx.Menu.AddNode(pkg.EcosystemID(), pkg.EcosystemName(), postsDir, "", []string{},
+			[]menu.BreadCrumb{{Name: "GitHub Advisories", Url: "/ghsa"}}, "github", false)
+	}
+	return nil
+}
+
+func GHSAPostToMarkdown(a GHSAAdvisory, w io.Writer, customContent string) error {
+	t := template.Must(template.New("ghsaPost").Funcs(gtf.GtfTextFuncMap).Parse(ghsaPostTemplate))
+	if err := t.Execute(w, a); err != nil {
+		return err
+	}
+	if customContent != "" {
+		_, _ = io.WriteString(w, "\n"+customContent)
+	}
+	return nil
+}
+
+const ghsaPostTemplate = `---
+title: "{{.ID}}"
+aliases: [
+	"/ghsa/{{lower .ID}}"
+]
+
+shortName: {{printf "%q" .Summary}}
+date: {{.Published}}
+category: vulnerabilities
+draft: false
+source: GitHub
+severity: {{.Severity | lower}}
+
+avd_page_type: ghsa_page
+
+date_published: {{.Published}}
+date_modified: {{.Updated}}
+
+header_subtitle: {{printf "%q" .Summary}}
+
+sidebar_additional_info_ghsa: "{{.URL}}"
+
+cvss_ghsa_vector: "{{.CVSSVector | default "N/A"}}"
+cvss_ghsa_score: "{{.CVSSScore}}"
+
+breadcrumbs:
+  - name: GitHub Advisories
+    path: /ghsa
+{{- with index .Packages 0}}
+  - name: {{.EcosystemName}}
+    path: /ghsa/{{.EcosystemID}}
+{{- end}}
+
+---
+
+### {{.Summary}}
+{{.Description}}
+
+### Affected Packages {.with_icon .affected_software}
+| Ecosystem | Package | Vulnerable Versions | First Patched Version |
+| ------------- |-------------|-----|----|{{range $p := .Packages}}{{range $r := $p.Ranges}}
+| {{$p.EcosystemName}} | {{$p.Name}} | {{$r.Vulnerable}} | {{$r.FirstPatched | default "None"}} |{{end}}{{end}}
+
+### References  {.with_icon .references}
+- {{.URL}}{{range $element := .References}}
+- {{$element}}{{end}}
+
+<!--- Add Aqua content below --->`
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
+)
+
+func TestLoadGHSAAdvisories(t *testing.T) {
+	advisories, err := loadGHSAAdvisories(newTestContext(nil, t.TempDir(), nil), "../goldens/json/ghsa")
+	require.NoError(t, err)
+
+	// the withdrawn GHSA-9w5f-2rqx-4mfp is left out
+	require.Len(t, advisories, 2)
+
+	log4j := advisories["GHSA-jfh8-c2jp-5v3q"]
+	require.NotNil(t, log4j)
+	assert.Equal(t, []string{"CVE-2021-44228"}, log4j.CVEIDs)
+	assert.Equal(t, "CRITICAL", log4j.Severity)
+	assert.Equal(t, []GHSAPackage{
+		{
+			Ecosystem: "MAVEN",
+			Name:      "org.apache.logging.log4j:log4j-api",
+			Ranges:    []GHSAVersionRange{{Vulnerable: "< 2.15.0", FirstPatched: "2.15.0"}},
+		},
+		{
+			Ecosystem: "MAVEN",
+			Name:      "org.apache.logging.log4j:log4j-core",
+			Ranges: []GHSAVersionRange{
+				{Vulnerable: ">= 2.13.0, < 2.15.0", FirstPatched: "2.15.0"},
+				{Vulnerable: "< 2.3.1", FirstPatched: "2.3.1"},
+				{Vulnerable: ">= 2.4, < 2.12.2", FirstPatched: "2.12.2"},
+			},
+		},
+	}, log4j.Packages)
+	assert.Equal(t, "Maven", log4j.Packages[0].EcosystemName())
+
+	byCVE := ghsaByCVE(advisories)
+	assert.Len(t, byCVE, 1)
+	assert.Len(t, byCVE["CVE-2021-44228"], 1)
+}
+
+func TestLoadGHSAAdvisoriesInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "npm", "broken"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "npm", "broken", "GHSA-xxxx-xxxx-xxxx.json"), []byte("{"), 0600))
+
+	ctx := newTestContext(nil, t.TempDir(), nil)
+	ctx.Report = report.New()
+	advisories, err := loadGHSAAdvisories(ctx, dir)
+	require.NoError(t, err)
+	assert.Empty(t, advisories)
+	assert.Equal(t, 1, ctx.Report.Count("test", report.StageParse))
+
+	// the sources of a run sharing the advisories read and record them once
+	ctx.Report = report.New()
+	nvdCtx := newTestContext(nil, t.TempDir(), nil)
+	nvdCtx.Source.Name, nvdCtx.Report = "nvd", ctx.Report
+	for _, c := range []*source.Context{ctx, nvdCtx} {
+		_, err := sharedGHSAAdvisories(c, dir)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, ctx.Report.Count("test", report.StageParse))
+	assert.Equal(t, 0, ctx.Report.Count("nvd", report.StageParse))
+}
+
+func TestGenerateGHSAPages(t *testing.T) {
+	pagesDir := t.TempDir()
+	ctx := newTestContext(map[string]string{"ghsa": "../goldens/json/ghsa"}, pagesDir, menu.New("vulnerabilities", pagesDir))
+
+	result := source.Run(ctx, ghsaSource{})
+	require.NoError(t, result.Err)
+	// advisories with a CVE ID are on its page
+	assert.Equal(t, 1, result.Documents)
+
+	got, err := os.ReadFile(filepath.Join(pagesDir, "go", "GHSA-35vf-776h-6hmr.md"))
+	require.NoError(t, err)
+	want, err := os.ReadFile("../goldens/ghsa/GHSA-35vf-776h-6hmr.md")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+
+	require.NoError(t, ctx.Menu.Generate())
+	index, err := os.ReadFile(filepath.Join(pagesDir, "go", "_index.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "title: Go\n")
+}
+
+func TestGenerateVulnerabilityPageGHSA(t *testing.T) {
+	ctx := newTestContext(nil, t.TempDir(), nil)
+	advisories, err := loadGHSAAdvisories(ctx, "../goldens/json/ghsa")
+	require.NoError(t, err)
+
+	nvdDir := "../goldens/json/nvd-ghsa"
+	postsDir := t.TempDir()
+	g := &vulnGenerator{ctx: ctx, cweDir: "../goldens/cwe", postsDir: postsDir, ghsa: ghsaByCVE(advisories)}
+
+	require.Equal(t, pageWritten, g.generatePage(filepath.Join(nvdDir, "CVE-2021-44228.json"), postsDir))
+	b, err := os.ReadFile(filepath.Join(postsDir, "CVE-2021-44228.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `aliases: [
+	"/nvd/cve-2021-44228",
+	"/ghsa/ghsa-jfh8-c2jp-5v3q"
+]
+`)
+	assert.Contains(t, string(b), `
+### GitHub Security Advisories {.with_icon .affected_software}
+| Advisory | Ecosystem | Package | Vulnerable Versions | First Patched Version |
+| ------------- |-------------|-----|----|----|
+| [GHSA-jfh8-c2jp-5v3q](https://github.com/advisories/GHSA-jfh8-c2jp-5v3q) | Maven | org.apache.logging.log4j:log4j-api | < 2.15.0 | 2.15.0 |
+| [GHSA-jfh8-c2jp-5v3q](https://github.com/advisories/GHSA-jfh8-c2jp-5v3q) | Maven | org.apache.logging.log4j:log4j-core | >= 2.13.0, < 2.15.0 | 2.15.0 |
+| [GHSA-jfh8-c2jp-5v3q](https://github.com/advisories/GHSA-jfh8-c2jp-5v3q) | Maven | org.apache.logging.log4j:log4j-core | < 2.3.1 | 2.3.1 |
+| [GHSA-jfh8-c2jp-5v3q](https://github.com/advisories/GHSA-jfh8-c2jp-5v3q) | Maven | org.apache.logging.log4j:log4j-core | >= 2.4, < 2.12.2 | 2.12.2 |
+`)
+}
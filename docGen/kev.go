@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/leekchan/gtf"
+
+	"github.com/aquasecurity/avd-generator/menu"
+)
+
+// KnownExploited is the entry of a CVE in the CISA Known Exploited
+// Vulnerabilities catalog.
+type KnownExploited struct {
+	CVEID                      string `json:"cveID"`
+	VendorProject              string `json:"vendorProject"`
+	Product                    string `json:"product"`
+	VulnerabilityName          string `json:"vulnerabilityName"`
+	DateAdded                  string `json:"dateAdded"`
+	ShortDescription           string `json:"shortDescription"`
+	RequiredAction             string `json:"requiredAction"`
+	DueDate                    string `json:"dueDate"`
+	KnownRansomwareCampaignUse string `json:"knownRansomwareCampaignUse"`
+	Notes                      string `json:"notes"`
+}
+
+// Year is the year the CVE was added to the catalog.
+func (k KnownExploited) Year() string {
+	year, _, _ := strings.Cut(k.DateAdded, "-")
+	return year
+}
+
+type kevCatalog struct {
+	CatalogVersion  string           `json:"catalogVersion"`
+	DateReleased    string           `json:"dateReleased"`
+	Vulnerabilities []KnownExploited `json:"vulnerabilities"`
+}
+
+// loadKEVCatalog reads a copy of the catalog published at
+// https://www.cisa.gov/known-exploited-vulnerabilities-catalog and indexes it by CVE ID.
+func loadKEVCatalog(path string) (map[string]KnownExploited, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog kevCatalog
+	if err := json.Unmarshal(b, &catalog); err != nil {
+		return nil, err
+	}
+
+	kev := make(map[string]KnownExploited, len(catalog.Vulnerabilities))
+	for _, v := range catalog.Vulnerabilities {
+		if v.CVEID == "" {
+			continue
+		}
+		kev[v.CVEID] = v
+	}
+	return kev, nil
+}
+
+type kevListPage struct {
+	Year    string
+	Entries []KnownExploited
+}
+
+// generateKEVPages writes a page listing the CVEs added to the catalog in each
+// year, most recently added first, and an index of those years.
+func generateKEVPages(postsDir string, kev map[string]KnownExploited) error {
+	kevDir := filepath.Join(postsDir, "known-exploited")
+	if err := os.MkdirAll(kevDir, 0755); err != nil {
+		return err
+	}
+
+	byYear := make(map[string][]KnownExploited)
+	for _, k := range kev {
+		byYear[k.Year()] = append(byYear[k.Year()], k)
+	}
+
+	var years []string
+	for year := range byYear {
+		years = append(years, year)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(years)))
+
+	index := menu.NewTopLevelMenu("Known Exploited", "toplevel_page", filepath.Join(kevDir, "_index.md")).
+		WithHeading("Known Exploited Vulnerabilities").
+		WithIcon("aqua").
+		WithCategory("vulnerabilities").
+		WithMenu("none")
+
+	t := template.Must(template.New("kevList").Funcs(gtf.GtfTextFuncMap).Parse(kevListTemplate))
+	for _, year := range years {
+		entries := byYear[year]
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].DateAdded != entries[j].DateAdded {
+				return entries[i].DateAdded > entries[j].DateAdded
+			}
+			return entries[i].CVEID < entries[j].CVEID
+		})
+
+		var page bytes.Buffer
+		if err := t.Execute(&page, kevListPage{Year: year, Entries: entries}); err != nil {
+			return fmt.Errorf("unable to render known exploited page for %s: %w", year, err)
+		}
+		if _, err := writeIfChanged(filepath.Join(kevDir, fmt.Sprintf("%s.md", year)), page.Bytes()); err != nil {
+			return fmt.Errorf("unable to write known exploited page for %s: %w", year, err)
+		}
+
+		index.WithTile(menu.Tile{
+			Heading: year,
+			Icon:    "cve",
+			Summary: fmt.Sprintf("%d CVE's added in %s", len(entries), year),
+			URL:     fmt.Sprintf("/nvd/known-exploited/%s/", year),
+		})
+	}
+	return index.Generate()
+}
+
+const kevListTemplate = `---
+title: "Known Exploited Vulnerabilities added in {{.Year}}"
+draft: false
+category: vulnerabilities
+
+avd_page_type: kev_page
+---
+
+CVEs added to the [CISA Known Exploited Vulnerabilities catalog](https://www.cisa.gov/known-exploited-vulnerabilities-catalog) in {{.Year}}, most recently added first.
+
+| CVE | Vendor | Product | Name | Date Added | Due Date | Known Ransomware Campaign Use |
+| ------------- |-------------|-----|----|----|----|----|{{range $k := .Entries}}
+| [{{$k.CVEID}}](/nvd/{{lower $k.CVEID}}) | {{$k.VendorProject}} | {{$k.Product}} | {{$k.VulnerabilityName}} | {{$k.DateAdded}} | {{$k.DueDate}} | {{$k.KnownRansomwareCampaignUse}} |{{end}}
+`
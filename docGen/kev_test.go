@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/avd-generator/manifest"
+)
+
+const kevGolden = "../goldens/json/kev/known_exploited_vulnerabilities.json"
+
+func TestLoadKEVCatalog(t *testing.T) {
+	kev, err := loadKEVCatalog(kevGolden)
+	require.NoError(t, err)
+	require.Len(t, kev, 3)
+
+	assert.Equal(t, KnownExploited{
+		CVEID:                      "CVE-2020-0002",
+		VendorProject:              "Android",
+		Product:                    "Android",
+		VulnerabilityName:          "Android Media Framework Use-After-Free Vulnerability",
+		DateAdded:                  "2021-11-03",
+		ShortDescription:           "A use-after-free in ih264d decoding of the Android media framework could lead to remote code execution.",
+		RequiredAction:             "Apply updates per vendor instructions.",
+		DueDate:                    "2022-05-03",
+		KnownRansomwareCampaignUse: "Unknown",
+	}, kev["CVE-2020-0002"])
+	assert.Equal(t, "2021", kev["CVE-2020-0002"].Year())
+
+	_, err = loadKEVCatalog("../goldens/json/kev/missing.json")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateKEVPages(t *testing.T) {
+	kev, err := loadKEVCatalog(kevGolden)
+	require.NoError(t, err)
+
+	postsDir := t.TempDir()
+	require.NoError(t, generateKEVPages(postsDir, kev))
+
+	files, err := getAllFiles(filepath.Join(postsDir, "known-exploited"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(postsDir, "known-exploited", "2021.md"),
+		filepath.Join(postsDir, "known-exploited", "2023.md"),
+		filepath.Join(postsDir, "known-exploited", "_index.md"),
+	}, files)
+
+	b, err := os.ReadFile(filepath.Join(postsDir, "known-exploited", "2021.md"))
+	require.NoError(t, err)
+	assert.Equal(t, `---
+title: "Known Exploited Vulnerabilities added in 2021"
+draft: false
+category: vulnerabilities
+
+avd_page_type: kev_page
+---
+
+CVEs added to the [CISA Known Exploited Vulnerabilities catalog](https://www.cisa.gov/known-exploited-vulnerabilities-catalog) in 2021, most recently added first.
+
+| CVE | Vendor | Product | Name | Date Added | Due Date | Known Ransomware Campaign Use |
+| ------------- |-------------|-----|----|----|----|----|
+| [CVE-2021-44228](/nvd/cve-2021-44228) | Apache | Log4j2 | Apache Log4j2 Remote Code Execution Vulnerability | 2021-12-10 | 2021-12-24 | Known |
+| [CVE-2020-0002](/nvd/cve-2020-0002) | Android | Android | Android Media Framework Use-After-Free Vulnerability | 2021-11-03 | 2022-05-03 | Unknown |
+`, string(b))
+
+	b, err = os.ReadFile(filepath.Join(postsDir, "known-exploited", "_index.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `  - heading: 2023
+    url: /nvd/known-exploited/2023/
+    icon: cve
+    summary: 1 CVE's added in 2023
+  - heading: 2021
+    url: /nvd/known-exploited/2021/`)
+
+	// an unchanged year page isn't written again
+	pageFile := filepath.Join(postsDir, "known-exploited", "2021.md")
+	before, err := os.Stat(pageFile)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, generateKEVPages(postsDir, kev))
+	after, err := os.Stat(pageFile)
+	require.NoError(t, err)
+	assert.Equal(t, before.ModTime(), after.ModTime())
+}
+
+func TestGenerateVulnerabilityPageKnownExploited(t *testing.T) {
+	kev, err := loadKEVCatalog(kevGolden)
+	require.NoError(t, err)
+
+	nvdDir := "../goldens/json/nvd"
+	postsDir := t.TempDir()
+	m := manifest.New(nvdManifestVersion(""))
+	g := &vulnGenerator{ctx: newTestContext(nil, postsDir, nil), manifest: m, cweDir: "../goldens/cwe", postsDir: postsDir, kev: kev, vendors: testVendors()}
+
+	require.Equal(t, pageWritten, g.generatePage(filepath.Join(nvdDir, "CVE-2020-0002.json"), postsDir))
+	b, err := os.ReadFile(filepath.Join(postsDir, "CVE-2020-0002.md"))
+	require.NoError(t, err)
+	page := string(b)
+	assert.Contains(t, page, `ubuntu_severity: "LOW"
+
+debian_severity: "N/A"
+
+known_exploited: true
+kev_date_added: 2021-11-03
+kev_due_date: 2022-05-03
+kev_ransomware_use: "Unknown"
+
+---`)
+	assert.Contains(t, page, `Android ID: A-142602711
+### Known Exploited {.with_icon .weakness}
+This vulnerability is actively exploited and is listed in the [CISA Known Exploited Vulnerabilities catalog](https://www.cisa.gov/known-exploited-vulnerabilities-catalog) as *Android Media Framework Use-After-Free Vulnerability*.
+
+| Date Added | Due Date | Known Ransomware Campaign Use |
+| ------------- |-------------|-----|
+| 2021-11-03 | 2022-05-03 | Unknown |
+
+**Required Action:** Apply updates per vendor instructions.
+
+### Weakness`)
+
+	require.Equal(t, pageWritten, g.generatePage(filepath.Join(nvdDir, "CVE-2020-0001.json"), postsDir))
+	b, err = os.ReadFile(filepath.Join(postsDir, "CVE-2020-0001.md"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "known_exploited")
+
+	// a change to the catalog entry renders the page again
+	assert.Equal(t, pageSkipped, g.generatePage(filepath.Join(nvdDir, "CVE-2020-0002.json"), postsDir))
+	k := kev["CVE-2020-0002"]
+	k.KnownRansomwareCampaignUse = "Known"
+	g.kev = map[string]KnownExploited{"CVE-2020-0002": k}
+	assert.Equal(t, pageWritten, g.generatePage(filepath.Join(nvdDir, "CVE-2020-0002.json"), postsDir))
+	g.kev = nil
+	assert.Equal(t, pageWritten, g.generatePage(filepath.Join(nvdDir, "CVE-2020-0002.json"), postsDir))
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 
 	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/aquasecurity/avd-generator/util"
 	"gopkg.in/yaml.v3"
 )
@@ -33,10 +34,16 @@ type KubeBenchConfig struct {
 	} `yaml:"groups"`
 }
 
-func generateKubeBenchPages(configDir, outputDir string) {
-	var configs []KubeBenchConfig
+func init() {
+	source.Register("kube-bench", func() source.Source { return kubeBenchSource{} })
+}
+
+type kubeBenchSource struct{}
+
+func (kubeBenchSource) Load(ctx *source.Context) ([]source.Document, error) {
+	var docs []source.Document
 
-	if err := filepath.Walk(configDir, func(path string, info fs.FileInfo, err error) error {
+	if err := filepath.Walk(ctx.Input("cfg"), func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -55,15 +62,20 @@ func generateKubeBenchPages(configDir, outputDir string) {
 			return err
 		}
 
-		configs = append(configs, config)
+		docs = append(docs, source.Document{ID: config.ID, Path: path, Data: config})
 
 		return nil
 	}); err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
+	return docs, nil
+}
+
+func (kubeBenchSource) Render(ctx *source.Context, docs []source.Document) error {
 	versionedConfigs := make(map[string]map[string]KubeBenchConfig)
 
-	for _, config := range configs {
+	for _, doc := range docs {
+		config := doc.Data.(KubeBenchConfig)
 		if _, ok := versionedConfigs[config.Version]; !ok {
 			versionedConfigs[config.Version] = make(map[string]KubeBenchConfig)
 		}
@@ -75,12 +87,10 @@ func generateKubeBenchPages(configDir, outputDir string) {
 		versionedConfigs[config.Version] = configTypeMap
 	}
 
-	if err := writeTemplates(versionedConfigs, outputDir); err != nil {
-		fmt.Println(err)
-	}
+	return writeTemplates(versionedConfigs, ctx.Output(), ctx.Menu)
 }
 
-func writeTemplates(versionedConfigs map[string]map[string]KubeBenchConfig, outputDir string) error {
+func writeTemplates(versionedConfigs map[string]map[string]KubeBenchConfig, outputDir string, complianceMenu *menu.Menu) error {
 	complianceMenu.AddNode("kubernetes", "Kubernetes", outputDir, "compliance", []string{},
 		[]menu.BreadCrumb{{Name: "Compliance", Url: "/compliance"}}, "kubernetes", true)
 
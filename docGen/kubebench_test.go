@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -12,7 +14,8 @@ import (
 func TestKubeBenchPages(t *testing.T) {
 
 	pagesDir := t.TempDir()
-	generateKubeBenchPages("../goldens/kube-bench/originals", pagesDir)
+	result := source.Run(newTestContext(map[string]string{"cfg": "../goldens/kube-bench/originals"}, pagesDir, menu.New("compliance", pagesDir)), kubeBenchSource{})
+	require.NoError(t, result.Err)
 	gotBytes, err := ioutil.ReadFile(filepath.Join(pagesDir, "kubernetes", "ack-1.0", "ack-1.0-controlplane", "3.1.md"))
 	require.NoError(t, err)
 
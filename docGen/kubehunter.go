@@ -8,29 +8,52 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
 )
 
-func generateKubeHunterPages(inputPagesDir string, outputPagesDir string) {
-	log.Printf("generating kube-hunter pages in: %s...", outputPagesDir)
+func init() {
+	source.Register("kube-hunter", func() source.Source { return kubeHunterSource{} })
+}
 
-	if err := os.MkdirAll(outputPagesDir, 0777); err != nil {
-		fail(err)
-	}
-	pages, err := getAllFiles(inputPagesDir)
+type kubeHunterSource struct{}
+
+func (kubeHunterSource) Load(ctx *source.Context) ([]source.Document, error) {
+	pages, err := getAllFiles(ctx.Input("docs"))
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	titleRegex := regexp.MustCompile("(?m)title: (.+)$")
-
+	var docs []source.Document
 	for _, page := range pages {
 		b, err := ioutil.ReadFile(page)
 		if err != nil {
-			log.Println("unable to read original kube hunter doc: ", err)
+			ctx.RecordError(page, report.StageRead, err)
 			continue
 		}
+		docs = append(docs, source.Document{
+			ID:   strings.ToLower(strings.TrimSuffix(filepath.Base(page), ".md")),
+			Path: page,
+			Data: b,
+		})
+	}
+	return docs, nil
+}
+
+func (kubeHunterSource) Render(ctx *source.Context, docs []source.Document) error {
+	outputPagesDir := ctx.Output()
+	log.Printf("generating kube-hunter pages in: %s...", outputPagesDir)
 
-		id := strings.ToLower(strings.TrimSuffix(filepath.Base(page), ".md"))
+	if err := os.MkdirAll(outputPagesDir, 0777); err != nil {
+		return err
+	}
+
+	titleRegex := regexp.MustCompile("(?m)title: (.+)$")
+
+	for _, doc := range docs {
+		b := doc.Data.([]byte)
+		id := doc.ID
 		title := titleRegex.FindSubmatch(b)[1]
 
 		newContent := strings.Replace(string(b), "---", fmt.Sprintf(`---
@@ -67,9 +90,9 @@ breadcrumbs:
 ### %s`, string(title)))
 		content := r.Replace(newContent)
 
-		err = ioutil.WriteFile(filepath.Join(outputPagesDir, filepath.Base(page)), []byte(content), 0644)
-		if err != nil {
-			log.Fatalln("unable to write kube hunter page: ", err)
+		if err := ioutil.WriteFile(filepath.Join(outputPagesDir, filepath.Base(doc.Path)), []byte(content), 0644); err != nil {
+			return fmt.Errorf("unable to write kube hunter page: %w", err)
 		}
 	}
+	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -16,7 +17,9 @@ func TestKubeHunterPages(t *testing.T) {
 		_ = os.RemoveAll(pagesDir)
 	}()
 
-	generateKubeHunterPages("../goldens/kube-hunter", pagesDir)
+	result := source.Run(newTestContext(map[string]string{"docs": "../goldens/kube-hunter"}, pagesDir, nil), kubeHunterSource{})
+	require.NoError(t, result.Err)
+
 	gotBytes, err := ioutil.ReadFile(filepath.Join(pagesDir, "KHV002-orig.md"))
 	require.NoError(t, err)
 
@@ -1,20 +1,18 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
 	"os"
-	"strconv"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/aquasecurity/avd-generator/config"
 	"github.com/aquasecurity/avd-generator/menu"
-)
-
-var (
-	Years []string
-
-	misConfigurationMenu = menu.New("misconfig", "content/misconfig")
-	complianceMenu       = menu.New("compliance", "content/compliance")
-	runTimeSecurityMenu  = menu.New("runsec", "content/tracee")
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
 )
 
 type Clock interface {
@@ -33,60 +31,156 @@ func (realClock) Now(format ...string) string {
 }
 
 func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fail(err)
+	}
+}
 
-	firstYear := 1999
+func run(args []string) error {
+	global := flag.NewFlagSet("generator", flag.ExitOnError)
+	configPath := global.String("config", "", "path to a generator config file, the built-in config is used when empty")
+	global.Usage = func() { usage(global, config.Default()) }
+	if err := global.Parse(args); err != nil {
+		return err
+	}
 
-	for y := firstYear; y <= time.Now().Year(); y++ {
-		Years = append(Years, strconv.Itoa(y))
+	cfg := config.Default()
+	if *configPath != "" {
+		var err error
+		if cfg, err = config.Load(*configPath); err != nil {
+			return err
+		}
 	}
 
-	generateChainBenchPages("../avd-repo/chain-bench-repo/internal/checks", "../avd-repo/content/compliance")
-	generateKubeBenchPages("../avd-repo/kube-bench-repo/cfg", "../avd-repo/content/compliance")
-	generateDefsecComplianceSpecPages("../avd-repo/trivy-policies-repo/rules/specs/compliance", "../avd-repo/content/compliance")
-	generateKubeHunterPages("../avd-repo/kube-hunter-repo/docs/_kb", "../avd-repo/content/misconfig/kubernetes")
-	generateCloudSploitPages("../avd-repo/cloudsploit-repo/plugins", "../avd-repo/content/misconfig", "../avd-repo/remediations-repo/en")
-	generateTraceePages("../avd-repo/tracee-repo/signatures", "../avd-repo/content/tracee", realClock{})
-	generateDefsecPages("../avd-repo/trivy-policies-repo/avd_docs", "../avd-repo/content/misconfig")
+	args = global.Args()
+	name := "all"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
 
-	generateVulnPages()
+	sources, err := selectSources(cfg, name)
+	if err != nil {
+		return err
+	}
 
-	for _, year := range Years {
-		generateReservedPages(year, realClock{}, "vuln-list-nvd", "content/nvd")
+	generators := make([]source.Source, len(sources))
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.StringVar(&cfg.ContentDir, "content", cfg.ContentDir, "root directory the markdown content is written to")
+	fs.StringVar(&cfg.Report, "report", cfg.Report, "path the JSON run report is written to, none is written when empty")
+	repos := make(map[string]*string)
+	types := make(map[string]bool)
+	for i, src := range sources {
+		generators[i], _ = source.New(src.Type)
+		for _, repo := range cfg.ReposOf(src) {
+			if _, ok := repos[repo]; !ok {
+				repos[repo] = fs.String(repo, cfg.Repos[repo], fmt.Sprintf("path to the %s checkout", repo))
+			}
+		}
+		if setter, ok := generators[i].(source.FlagSetter); ok && !types[src.Type] {
+			setter.SetFlags(fs, &sources[i])
+			types[src.Type] = true
+		}
+	}
+	fs.Usage = func() { usage(fs, cfg) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for repo, path := range repos {
+		cfg.Repos[repo] = *path
 	}
 
-	createTopLevelMenus()
-}
+	menus := make(map[string]*menu.Menu)
+	runReport := report.New()
+	var failed []string
+	for i, src := range sources {
+		ctx := &source.Context{Config: cfg, Source: src, Report: runReport}
+		if src.Menu != "" {
+			if ctx.Menu = menus[src.Menu]; ctx.Menu == nil {
+				mc, _ := cfg.Menu(src.Menu)
+				ctx.Menu = menu.New(mc.Category, cfg.MenuDir(mc))
+				menus[src.Menu] = ctx.Menu
+			}
+		}
+
+		log.Printf("running source %s (%s)", src.Name, src.Type)
+		result := source.Run(ctx, generators[i])
+		if result.Err != nil {
+			log.Printf("source %s failed after %s: %s", result.Name, result.Duration.Round(time.Millisecond), result.Err)
+			failed = append(failed, result.Name)
+			continue
+		}
+		log.Printf("source %s rendered %d documents in %s (%d errors)", result.Name, result.Documents,
+			result.Duration.Round(time.Millisecond), runReport.Count(result.Name, ""))
+	}
 
-func createTopLevelMenus() {
-	if err := menu.NewTopLevelMenu("Misconfiguration", "toplevel_page", "content/misconfig/_index.md").
-		WithHeading("Misconfiguration Categories").
-		WithIcon("aqua").
-		WithCategory("misconfig").Generate(); err != nil {
-		fail(err)
+	if err := createTopLevelMenus(cfg, menus); err != nil {
+		return err
 	}
-	if err := menu.NewTopLevelMenu("Compliance", "toplevel_page", "content/compliance/_index.md").
-		WithHeading("Compliance").
-		WithIcon("aqua").
-		WithCategory("compliance").Generate(); err != nil {
-		fail(err)
+	if cfg.Report != "" {
+		if err := runReport.Write(cfg.Report); err != nil {
+			return fmt.Errorf("unable to write run report: %w", err)
+		}
+		log.Printf("run report written to %s", cfg.Report)
 	}
-	if err := menu.NewTopLevelMenu("Tracee", "toplevel_page", "content/tracee/_index.md").
-		WithHeading("Runtime Security").
-		WithIcon("tracee").
-		WithCategory("runsec").
-		Generate(); err != nil {
-		fail(err)
+	if len(failed) > 0 {
+		return fmt.Errorf("sources failed: %s", strings.Join(failed, ", "))
 	}
+	return runReport.Check(cfg.Thresholds)
+}
 
-	if err := misConfigurationMenu.Generate(); err != nil {
-		fail(err)
+func selectSources(cfg config.Config, name string) ([]config.Source, error) {
+	var sources []config.Source
+	if name == "all" {
+		sources = append(sources, cfg.Sources...)
+	} else if src, ok := cfg.Source(name); ok {
+		sources = append(sources, src)
+	} else {
+		return nil, fmt.Errorf("unknown command %q, run 'generator -h' for a list of commands", name)
 	}
-	if err := runTimeSecurityMenu.Generate(); err != nil {
-		fail(err)
+
+	for i, src := range sources {
+		if _, err := source.New(src.Type); err != nil {
+			return nil, fmt.Errorf("source %q: %w", src.Name, err)
+		}
+		// copy the options so flags don't write through to the config
+		options := make(map[string]string, len(src.Options))
+		for k, v := range src.Options {
+			options[k] = v
+		}
+		sources[i].Options = options
 	}
-	if err := complianceMenu.Generate(); err != nil {
-		fail(err)
+	return sources, nil
+}
+
+func usage(fs *flag.FlagSet, cfg config.Config) {
+	fmt.Fprintf(fs.Output(), "Usage: generator [-config file] [command] [flags]\n\nCommands:\n")
+	fmt.Fprintf(fs.Output(), "  %-12s %s\n", "all", "run every source (default)")
+	for _, src := range cfg.Sources {
+		fmt.Fprintf(fs.Output(), "  %-12s generate %s pages in %s\n", src.Name, src.Type, cfg.Output(src))
+	}
+	fmt.Fprintf(fs.Output(), "\nSource types: %s\n", strings.Join(source.Types(), ", "))
+	fmt.Fprintf(fs.Output(), "\nFlags for %s:\n", fs.Name())
+	fs.PrintDefaults()
+}
+
+func createTopLevelMenus(cfg config.Config, menus map[string]*menu.Menu) error {
+	for _, mc := range cfg.Menus {
+		m, ok := menus[mc.Name]
+		if !ok {
+			continue
+		}
+		if err := menu.NewTopLevelMenu(mc.Title, "toplevel_page", filepath.Join(cfg.MenuDir(mc), "_index.md")).
+			WithHeading(mc.Heading).
+			WithIcon(mc.Icon).
+			WithCategory(mc.Category).
+			Generate(); err != nil {
+			return err
+		}
+		if err := m.Generate(); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 func fail(err error) {
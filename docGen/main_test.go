@@ -1,12 +1,43 @@
 package main
 
 import (
+	"path/filepath"
 	"testing"
 
+	"github.com/aquasecurity/avd-generator/config"
+	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// newTestContext returns a context for running a source against golden inputs,
+// writing its pages straight into outputDir.
+func newTestContext(inputs map[string]string, outputDir string, m *menu.Menu) *source.Context {
+	return &source.Context{
+		Config: config.Config{ContentDir: outputDir},
+		Source: config.Source{Name: "test", Inputs: inputs, Options: map[string]string{}},
+		Menu:   m,
+	}
+}
+
+// testVendors returns the vendor adapters of the golden vendor data.
+func testVendors() []VendorAdapter {
+	return []VendorAdapter{
+		newRedHatAdapter("../goldens/json/redhat"),
+		newUbuntuAdapter("../goldens/json/ubuntu"),
+	}
+}
+
+// reservedTestVendors returns the vendor adapters of the golden reserved CVEs in inputDir.
+func reservedTestVendors(inputDir string) []VendorAdapter {
+	return []VendorAdapter{
+		newRedHatAdapter(filepath.Join(inputDir, "redhat")),
+		newUbuntuAdapter(filepath.Join(inputDir, "ubuntu")),
+		newDebianAdapter(filepath.Join(inputDir, "debian")),
+	}
+}
+
 type fakeClock struct{}
 
 func (fakeClock) Now(format ...string) string {
@@ -18,3 +49,23 @@ func TestGetAllFiles(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []string{"../goldens/json/nvd/CVE-2020-0001.json", "../goldens/json/nvd/CVE-2020-0002.json", "../goldens/json/nvd/CVE-2020-11932.json", "../goldens/json/nvd/CVE-2022-2788.json"}, actual)
 }
+
+func TestSelectSources(t *testing.T) {
+	cfg := config.Default()
+
+	all, err := selectSources(cfg, "all")
+	require.NoError(t, err)
+	assert.Equal(t, len(cfg.Sources), len(all))
+
+	nvd, err := selectSources(cfg, "nvd")
+	require.NoError(t, err)
+	require.Len(t, nvd, 1)
+	assert.Equal(t, "nvd", nvd[0].Type)
+	assert.Equal(t, "cwe/cwec.xml", cfg.Input(nvd[0], "cwe"))
+
+	nvd[0].Options["first-year"] = "2020"
+	assert.Equal(t, "1999", cfg.Sources[len(cfg.Sources)-1].Options["first-year"], "options must be copied")
+
+	_, err = selectSources(cfg, "foo")
+	assert.EqualError(t, err, `unknown command "foo", run 'generator -h' for a list of commands`)
+}
@@ -0,0 +1,112 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// Entry records what a page was last rendered from and what was written.
+type Entry struct {
+	// Inputs are the files found while rendering the page, such as its CWE
+	// definition, that can't be derived from the page ID.
+	Inputs     []string `json:"inputs,omitempty"`
+	InputHash  string   `json:"input"`
+	OutputHash string   `json:"output"`
+}
+
+// Manifest maps page IDs to the hashes of their inputs and output, so that a
+// page is only rendered again when one of them changes. It is safe for
+// concurrent use.
+type Manifest struct {
+	mu sync.Mutex
+	// Version identifies how the pages were rendered, a manifest written by
+	// another version is discarded so every page is rendered again.
+	Version string           `json:"version"`
+	Entries map[string]Entry `json:"entries"`
+}
+
+func New(version string) *Manifest {
+	return &Manifest{Version: version, Entries: make(map[string]Entry)}
+}
+
+// Load reads the manifest at path. A missing manifest, or one written by
+// another version, gives an empty manifest.
+func Load(path, version string) (*Manifest, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return New(version), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	if m.Version != version || m.Entries == nil {
+		return New(version), nil
+	}
+	return &m, nil
+}
+
+func (m *Manifest) Save(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+// Get returns the entry of a page. A nil manifest has no entries, so every
+// page is rendered.
+func (m *Manifest) Get(id string) (Entry, bool) {
+	if m == nil {
+		return Entry{}, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.Entries[id]
+	return e, ok
+}
+
+func (m *Manifest) Set(id string, e Entry) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Entries[id] = e
+}
+
+// HashFiles hashes the content of files in order. A missing file hashes
+// differently from an empty one, so adding or removing an input changes the hash.
+func HashFiles(paths ...string) (string, error) {
+	h := sha256.New()
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			h.Write([]byte{0})
+		case err != nil:
+			return "", err
+		default:
+			h.Write([]byte{1})
+			h.Write(b)
+		}
+		h.Write([]byte{0xff})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func HashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
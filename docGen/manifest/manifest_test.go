@@ -0,0 +1,54 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+
+	m, err := Load(path, "v1")
+	require.NoError(t, err)
+	assert.Empty(t, m.Entries)
+
+	m.Set("CVE-2020-0001", Entry{InputHash: "in", OutputHash: "out"})
+	require.NoError(t, m.Save(path))
+
+	m, err = Load(path, "v1")
+	require.NoError(t, err)
+	e, ok := m.Get("CVE-2020-0001")
+	require.True(t, ok)
+	assert.Equal(t, Entry{InputHash: "in", OutputHash: "out"}, e)
+
+	m, err = Load(path, "v2")
+	require.NoError(t, err)
+	assert.Empty(t, m.Entries)
+
+	var nilManifest *Manifest
+	nilManifest.Set("CVE-2020-0001", e)
+	_, ok = nilManifest.Get("CVE-2020-0001")
+	assert.False(t, ok)
+}
+
+func TestHashFiles(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	require.NoError(t, os.WriteFile(a, []byte("a"), 0600))
+
+	missing, err := HashFiles(a, b)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(b, nil, 0600))
+	empty, err := HashFiles(a, b)
+	require.NoError(t, err)
+	assert.NotEqual(t, missing, empty)
+
+	swapped, err := HashFiles(b, a)
+	require.NoError(t, err)
+	assert.NotEqual(t, empty, swapped)
+}
@@ -34,21 +34,23 @@ type menuNode struct {
 	icon         string
 }
 
-type menu struct {
+// Menu collects the nodes sources contribute to a section of the site and
+// writes an _index.md for each of them.
+type Menu struct {
 	rootMenu   string
 	contentDir string
 	nodes      map[string]menuNode
 }
 
-func New(rootMenu, contentDir string) *menu {
-	return &menu{
+func New(rootMenu, contentDir string) *Menu {
+	return &Menu{
 		rootMenu:   rootMenu,
 		contentDir: contentDir,
 		nodes:      make(map[string]menuNode),
 	}
 }
 
-func (m *menu) AddNode(id, name, contentDir string, parentID string, remediations []string, categories []BreadCrumb, icon string, topLevel bool) {
+func (m *Menu) AddNode(id, name, contentDir string, parentID string, remediations []string, categories []BreadCrumb, icon string, topLevel bool) {
 	id = strings.ToLower(strings.ReplaceAll(id, " ", "-"))
 	key := fmt.Sprintf("%s/%s", parentID, id)
 	var workingNode menuNode
@@ -72,7 +74,7 @@ func (m *menu) AddNode(id, name, contentDir string, parentID string, remediation
 	m.nodes[key] = workingNode
 }
 
-func (m *menu) topLevel() []menuNode {
+func (m *Menu) topLevel() []menuNode {
 	var topLevelNodes []menuNode
 
 	for _, node := range m.nodes {
@@ -88,7 +90,7 @@ func (m *menu) topLevel() []menuNode {
 	return topLevelNodes
 }
 
-func (m *menu) branches() []menuNode {
+func (m *Menu) branches() []menuNode {
 	var branches []menuNode
 
 	for _, node := range m.nodes {
@@ -99,7 +101,7 @@ func (m *menu) branches() []menuNode {
 	return branches
 }
 
-func (m *menu) Generate() error {
+func (m *Menu) Generate() error {
 
 	if err := m.generateTopLevelFile(); err != nil {
 		return err
@@ -109,7 +111,7 @@ func (m *menu) Generate() error {
 
 }
 
-func (m *menu) generateBranchFiles() error {
+func (m *Menu) generateBranchFiles() error {
 	for _, branch := range m.branches() {
 		branchFilePath := filepath.Join(branch.contentDir, branch.id, "_index.md")
 		if err := os.MkdirAll(filepath.Dir(branchFilePath), 0755); err != nil {
@@ -154,7 +156,7 @@ func (m *menu) generateBranchFiles() error {
 	return nil
 }
 
-func (m *menu) generateTopLevelFile() error {
+func (m *Menu) generateTopLevelFile() error {
 
 	for _, topLevel := range m.topLevel() {
 		providerFilePath := filepath.Join(topLevel.contentDir, topLevel.id, "_index.md")
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"slices"
 	"sort"
 	"strconv"
@@ -21,26 +26,18 @@ import (
 
 	"github.com/valyala/fastjson"
 
+	"github.com/aquasecurity/avd-generator/config"
+	"github.com/aquasecurity/avd-generator/cvss"
+	"github.com/aquasecurity/avd-generator/manifest"
 	"github.com/aquasecurity/avd-generator/menu"
-	"github.com/aquasecurity/vuln-list-update/redhat"
-	"github.com/aquasecurity/vuln-list-update/ubuntu"
-)
-
-var (
-	CVEMap map[string]map[string]ReservedCVEInfo
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
 )
 
 type ReservedPage struct {
 	ID     string
 	Date   string
-	CVEMap map[string]ReservedCVEInfo
-}
-
-type ReservedCVEInfo struct {
-	Description          string
-	Severity             string
-	Mitigation           string // Redhat publishes mitigation
-	AffectedSoftwareList []AffectedSoftware
+	CVEMap map[string]VendorInfo
 }
 
 type Dates struct {
@@ -53,15 +50,68 @@ type CVSS struct {
 	V2Score  float64
 	V3Vector string
 	V3Score  float64
+	V4Vector string
+	V4Score  float64
+	// V2Source, V3Source and V4Source are who published the scores of a
+	// vulnerability, such as nvd@nist.gov or the CNA.
+	V2Source string
+	V3Source string
+	V4Source string
 }
 
-type RedHatCVSSInfo struct {
-	CVSS
-	Severity string
+// VectorBreakdown is a CVSS vector shown metric by metric on a page.
+type VectorBreakdown struct {
+	Title   string
+	Metrics []cvss.Metric
 }
 
-type UbuntuCVSSInfo struct {
-	Severity string
+// VectorBreakdowns returns the NVD and Red Hat vectors that parse, newest CVSS version first.
+func (v Vulnerability) VectorBreakdowns() []VectorBreakdown {
+	var breakdowns []VectorBreakdown
+	add := func(title, vector string) {
+		if vector == "" {
+			return
+		}
+		if parsed, err := cvss.Parse(vector); err == nil {
+			breakdowns = append(breakdowns, VectorBreakdown{
+				Title:   fmt.Sprintf("%s v%s Vector", title, parsed.Version),
+				Metrics: parsed.Base(),
+			})
+		}
+	}
+	add("CVSS", v.CVSS.V4Vector)
+	add("CVSS", v.CVSS.V3Vector)
+	add("CVSS", v.CVSS.V2Vector)
+	redhat := v.Vendor("redhat")
+	add("Red Hat CVSS", redhat.CVSS.V3Vector)
+	add("Red Hat CVSS", redhat.CVSS.V2Vector)
+	return breakdowns
+}
+
+// checkScores reports the vectors that don't parse or don't add up to the score published with them.
+func (v Vulnerability) checkScores() []error {
+	var errs []error
+	check := func(source, vector string, score float64) {
+		parsed, err := cvss.Parse(vector)
+		if err == nil {
+			err = parsed.Check(score)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source, err))
+		}
+	}
+	for _, s := range v.Scores {
+		check(s.Source, s.Vector, s.Score)
+	}
+	// Red Hat publishes N/A rather than leaving vectors out
+	redhat := v.Vendor("redhat")
+	if vector := redhat.CVSS.V3Vector; vector != "" && vector != "N/A" {
+		check("redhat", vector, redhat.CVSS.V3Score)
+	}
+	if vector := redhat.CVSS.V2Vector; vector != "" && vector != "N/A" {
+		check("redhat", vector, redhat.CVSS.V2Score)
+	}
+	return errs
 }
 
 type AffectedSoftware struct {
@@ -69,21 +119,237 @@ type AffectedSoftware struct {
 	Vendor       string
 	StartVersion string
 	EndVersion   string
+	// Status and Notes are set by vendors that track the software they ship, such as Debian.
+	Status string
+	Notes  string
+}
+
+// Configuration is one of the NVD configurations a CVE applies to. Its nodes
+// are combined with Operator, which is empty when there is a single node.
+type Configuration struct {
+	Operator string
+	Negate   bool
+	Nodes    []ConfigurationNode
+}
+
+// ConfigurationNode combines its CPE matches, and the nodes of older feeds
+// nested below it, with Operator.
+type ConfigurationNode struct {
+	Operator string
+	Negate   bool
+	Matches  []CPEMatch
+	Children []ConfigurationNode
+}
+
+// CPEMatch is a product and version range. Matches that aren't vulnerable are
+// the platform the vulnerable software has to run on, or with.
+type CPEMatch struct {
+	Vulnerable     bool
+	Criteria       string
+	Vendor         string
+	Name           string
+	Version        string
+	StartIncluding string
+	StartExcluding string
+	EndIncluding   string
+	EndExcluding   string
+}
+
+// CVSSScore is a CVSS score published for a CVE, by NVD or by the CNA that assigned it.
+type CVSSScore struct {
+	Source string
+	// Type is Primary for the score of the CVE's source, Secondary for others.
+	Type     string
+	Version  string
+	Vector   string
+	Score    float64
+	Severity string
+}
+
+// defaultScorePrecedence prefers NVD's own scores and then the primary ones.
+var defaultScorePrecedence = []string{"nvd@nist.gov", "Primary", "Secondary"}
+
+func parseScores(metrics *fastjson.Value) []CVSSScore {
+	var scores []CVSSScore
+	for _, key := range []string{"cvssMetricV40", "cvssMetricV31", "cvssMetricV30", "cvssMetricV2"} {
+		for _, m := range metrics.GetArray(key) {
+			score := CVSSScore{
+				Source:   string(m.GetStringBytes("source")),
+				Type:     string(m.GetStringBytes("type")),
+				Version:  string(m.GetStringBytes("cvssData", "version")),
+				Vector:   string(m.GetStringBytes("cvssData", "vectorString")),
+				Score:    m.GetFloat64("cvssData", "baseScore"),
+				Severity: string(m.GetStringBytes("cvssData", "baseSeverity")),
+			}
+			if score.Severity == "" { // v2 keeps its severity next to cvssData
+				score.Severity = string(m.GetStringBytes("baseSeverity"))
+			}
+			scores = append(scores, score)
+		}
+	}
+	return scores
+}
+
+// selectScores sets the headline v2, v3 and v4 scores from the scores of the
+// first source or type in precedence that published one, preferring 3.1 over
+// 3.0. Scores matching nothing in precedence are only used when there is no other.
+func (v *Vulnerability) selectScores(precedence []string) {
+	rank := func(s CVSSScore) int {
+		for i, p := range precedence {
+			if p = strings.TrimSpace(p); strings.EqualFold(p, s.Source) || strings.EqualFold(p, s.Type) {
+				return i
+			}
+		}
+		return len(precedence)
+	}
+
+	scores := slices.Clone(v.Scores)
+	sort.SliceStable(scores, func(i, j int) bool {
+		if ri, rj := rank(scores[i]), rank(scores[j]); ri != rj {
+			return ri < rj
+		}
+		return scores[i].Version > scores[j].Version
+	})
+
+	v.CVSS, v.NVDSeverityV2, v.NVDSeverityV3, v.NVDSeverityV4 = CVSS{}, "", "", ""
+	for _, s := range scores {
+		switch {
+		case strings.HasPrefix(s.Version, "2") && v.CVSS.V2Vector == "":
+			v.CVSS.V2Vector, v.CVSS.V2Score, v.CVSS.V2Source, v.NVDSeverityV2 = s.Vector, s.Score, s.Source, s.Severity
+		case strings.HasPrefix(s.Version, "3") && v.CVSS.V3Vector == "":
+			v.CVSS.V3Vector, v.CVSS.V3Score, v.CVSS.V3Source, v.NVDSeverityV3 = s.Vector, s.Score, s.Source, s.Severity
+		case strings.HasPrefix(s.Version, "4") && v.CVSS.V4Vector == "":
+			v.CVSS.V4Vector, v.CVSS.V4Score, v.CVSS.V4Source, v.NVDSeverityV4 = s.Vector, s.Score, s.Source, s.Severity
+		}
+	}
 }
 
 type Vulnerability struct {
-	ID               string
-	CWEID            string
-	CWEInfo          WeaknessType
-	Description      string
-	References       []string
-	CVSS             CVSS
-	NVDSeverityV2    string
-	NVDSeverityV3    string
-	RedHatCVSSInfo   RedHatCVSSInfo
-	UbuntuCVSSInfo   UbuntuCVSSInfo
+	ID string
+	// Status is the vulnStatus of NVD, such as Analyzed, Awaiting Analysis,
+	// Modified, Rejected or Deferred.
+	Status string
+	// Disputed is set when a CNA tagged the CVE as disputed.
+	Disputed bool
+	// Weaknesses are the CWEs of the CVE in the order NVD lists them.
+	Weaknesses    []Weakness
+	Description   string
+	References    []string
+	CVSS          CVSS
+	NVDSeverityV2 string
+	NVDSeverityV3 string
+	NVDSeverityV4 string
+	// Scores are every CVSS score published for the CVE, CVSS holds the headline ones.
+	Scores           []CVSSScore
 	Dates            Dates
 	AffectedSoftware []AffectedSoftware
+	Configurations   []Configuration
+	// Vendors are what the vendor adapters know of the CVE, in the order of the vendors option.
+	Vendors []VendorInfo
+	// KnownExploited is set when the CVE is in the CISA KEV catalog.
+	KnownExploited *KnownExploited
+	// EPSS is set when the CVE is in the EPSS snapshot.
+	EPSS *EPSS
+	// Advisories are the GitHub Security Advisories of the CVE.
+	Advisories []GHSAAdvisory
+}
+
+// Weakness is a CWE a CVE is mapped to, or one of the states NVD maps CVEs
+// that no CWE fits to, such as NVD-CWE-noinfo.
+type Weakness struct {
+	ID string
+	// Sources are who mapped the CVE to the weakness, NVD and the CNA may both have.
+	Sources []WeaknessSource
+	// Info is set once the CWE is loaded from the CWE directory.
+	Info WeaknessType
+}
+
+// WeaknessSource is who mapped a CVE to a weakness, such as nvd@nist.gov, and
+// whether as the Primary or a Secondary source.
+type WeaknessSource struct {
+	Source string
+	Type   string
+}
+
+// nvdWeaknessStates are the names and descriptions NVD gives to the IDs it
+// maps CVEs to when no CWE fits.
+var nvdWeaknessStates = map[string][2]string{
+	"NVD-CWE-Other":  {"Other", "NVD is only using a subset of CWE for mapping instead of the entire CWE, and the weakness type is not covered by that subset."},
+	"NVD-CWE-noinfo": {"Insufficient Information", "There is insufficient information about the issue to classify it; details are unknown or unspecified."},
+}
+
+// IsCWE is whether the weakness is a CWE rather than a state of NVD.
+func (w Weakness) IsCWE() bool {
+	return strings.HasPrefix(w.ID, "CWE-")
+}
+
+// Title is the ID of the weakness followed by its name when known.
+func (w Weakness) Title() string {
+	name := w.Info.Name
+	if state, ok := nvdWeaknessStates[w.ID]; ok {
+		name = state[0]
+	}
+	if name == "" {
+		return w.ID
+	}
+	return w.ID + ": " + name
+}
+
+// Description describes the CWE when it is loaded, or the state of NVD.
+func (w Weakness) Description() string {
+	if state, ok := nvdWeaknessStates[w.ID]; ok {
+		return state[1]
+	}
+	return w.Info.Description
+}
+
+// Link is the page of the CWE, ours when it is loaded and MITRE's otherwise.
+// States of NVD have none.
+func (w Weakness) Link() string {
+	switch {
+	case !w.IsCWE():
+		return ""
+	case w.Info.ID != 0:
+		return "/cwe/" + strings.ToLower(w.ID)
+	default:
+		return fmt.Sprintf("https://cwe.mitre.org/data/definitions/%s.html", strings.TrimPrefix(w.ID, "CWE-"))
+	}
+}
+
+// MappedBy lists who mapped the CVE to the weakness.
+func (w Weakness) MappedBy() string {
+	sources := make([]string, len(w.Sources))
+	for i, s := range w.Sources {
+		sources[i] = fmt.Sprintf("%s (%s)", s.Source, s.Type)
+	}
+	return strings.Join(sources, ", ")
+}
+
+// CWEs are the weaknesses of the CVE that are CWEs.
+func (v Vulnerability) CWEs() []Weakness {
+	var cwes []Weakness
+	for _, w := range v.Weaknesses {
+		if w.IsCWE() {
+			cwes = append(cwes, w)
+		}
+	}
+	return cwes
+}
+
+// LoadedCWEs are the CWEs of the CVE that were loaded from the CWE directory.
+func (v Vulnerability) LoadedCWEs() []Weakness {
+	var cwes []Weakness
+	for _, w := range v.Weaknesses {
+		if w.Info.ID != 0 {
+			cwes = append(cwes, w)
+		}
+	}
+	return cwes
+}
+
+// Rejected is whether NVD rejected the CVE.
+func (v Vulnerability) Rejected() bool {
+	return v.Status == "Rejected"
 }
 
 type VulnerabilityPost struct {
@@ -95,25 +361,221 @@ type VulnerabilityPost struct {
 	Vulnerability Vulnerability
 }
 
-func generateVulnPages() {
-	postsDir := "content/nvd"
+func init() {
+	source.Register("nvd", func() source.Source { return nvdSource{clock: realClock{}} })
+}
 
-	var wg sync.WaitGroup
-	for _, year := range Years {
-		year := year
-		wg.Add(1)
+type nvdSource struct {
+	clock Clock
+}
 
-		log.Printf("generating vuln year: %s\n", year)
-		nvdDir := fmt.Sprintf("vuln-list-nvd/api/%s/", year)
-		cweDir := "vuln-list/cwe"
+func (nvdSource) SetFlags(fs *flag.FlagSet, src *config.Source) {
+	fs.Func("first-year", fmt.Sprintf("first year to generate vulnerability pages for (default %s)", src.Options["first-year"]), func(v string) error {
+		src.Options["first-year"] = v
+		return nil
+	})
+	fs.Func("concurrency", "number of CVE files rendered at once, across every year (default the number of CPUs)", func(v string) error {
+		src.Options["concurrency"] = v
+		return nil
+	})
+	fs.Func("score-precedence", fmt.Sprintf("comma separated sources or types (Primary, Secondary) whose CVSS scores are preferred for the headline scores (default %q)", src.Options["score-precedence"]), func(v string) error {
+		src.Options["score-precedence"] = v
+		return nil
+	})
+	fs.Func("vendors", fmt.Sprintf("comma separated vendors whose data enriches the pages, each read from the input of the same name (default %q)", src.Options["vendors"]), func(v string) error {
+		src.Options["vendors"] = v
+		return nil
+	})
+	fs.Func("epss-max-age", fmt.Sprintf("days after which the EPSS snapshot is reported as stale, it is still used (default %q)", src.Options["epss-max-age"]), func(v string) error {
+		src.Options["epss-max-age"] = v
+		return nil
+	})
+	fs.Func("manifest", fmt.Sprintf("manifest of the inputs each page was rendered from, pages whose inputs are unchanged are skipped. Empty renders every page (default %q)", src.Options["manifest"]), func(v string) error {
+		src.Options["manifest"] = v
+		return nil
+	})
+}
 
-		go func(year string) {
-			defer wg.Done()
-			generateVulnerabilityPages(nvdDir, cweDir, postsDir, year)
-		}(year)
+// Load returns a document per year of NVD data, from first-year up to the current year.
+func (nvdSource) Load(ctx *source.Context) ([]source.Document, error) {
+	firstYear, err := strconv.Atoi(ctx.Source.Options["first-year"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid first-year: %w", err)
+	}
+
+	var docs []source.Document
+	for y := firstYear; y <= time.Now().Year(); y++ {
+		year := strconv.Itoa(y)
+		docs = append(docs, source.Document{
+			ID:   year,
+			Path: fmt.Sprintf("%s/api/%s/", ctx.Input("nvd"), year),
+		})
+	}
+	return docs, nil
+}
+
+func (s nvdSource) Render(ctx *source.Context, docs []source.Document) error {
+	postsDir := ctx.Output()
+	cweDir := ctx.Input("cwe")
+
+	var m *manifest.Manifest
+	if path := ctx.Source.Options["manifest"]; path != "" {
+		var err error
+		if m, err = manifest.Load(path, nvdManifestVersion(ctx.Source.Options["score-precedence"])); err != nil {
+			return fmt.Errorf("unable to load manifest: %w", err)
+		}
+	}
+
+	concurrency := runtime.NumCPU()
+	if v := ctx.Source.Options["concurrency"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid concurrency %q", v)
+		}
+		concurrency = n
 	}
-	wg.Wait()
 
+	g := &vulnGenerator{
+		ctx:         ctx,
+		manifest:    m,
+		cweDir:      cweDir,
+		postsDir:    postsDir,
+		concurrency: concurrency,
+	}
+	adapters, err := newVendorAdapters(ctx)
+	if err != nil {
+		return err
+	}
+	g.vendors = adapters
+	if path := ctx.Input("kev"); path != "" {
+		kev, err := loadKEVCatalog(path)
+		switch {
+		case os.IsNotExist(err):
+			log.Printf("no known exploited vulnerabilities catalog at %s, pages are rendered without it", path)
+		case err != nil:
+			ctx.RecordError(path, report.StageLoad, err)
+		default:
+			g.kev = kev
+		}
+	}
+
+	if dir := ctx.Input("ghsa"); dir != "" {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			log.Printf("no GitHub advisories at %s, pages are rendered without them", dir)
+		} else {
+			advisories, err := sharedGHSAAdvisories(ctx, dir)
+			if err != nil {
+				return fmt.Errorf("unable to load GitHub advisories: %w", err)
+			}
+			g.ghsa = ghsaByCVE(advisories)
+		}
+	}
+	if dir := ctx.Input("osv"); dir != "" {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			log.Printf("no OSV records at %s, pages are rendered without them", dir)
+		} else {
+			osv, err := loadOSVRecords(ctx, dir)
+			if err != nil {
+				return fmt.Errorf("unable to load OSV records: %w", err)
+			}
+			g.osv = osv
+		}
+	}
+	if isCWECatalog(cweDir) {
+		c, err := loadCWECatalog(cweDir)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			log.Printf("no CWE catalogue at %s, CWEs link to cwe.mitre.org", cweDir)
+		case err != nil:
+			return fmt.Errorf("unable to load the CWE catalogue: %w", err)
+		default:
+			g.cweCatalog = c
+			if g.cweCatalogHash, err = manifest.HashFiles(cweDir); err != nil {
+				return err
+			}
+		}
+	}
+	if g.capec = loadCAPECInput(ctx); g.capec != nil {
+		if g.capecHash, err = manifest.HashFiles(ctx.Input("capec")); err != nil {
+			return err
+		}
+	}
+	if path := ctx.Input("epss"); path != "" {
+		epss, err := s.loadEPSS(ctx, path)
+		if err != nil {
+			return err
+		}
+		g.epss = epss
+	}
+
+	if err := g.generatePages(docs); err != nil {
+		return err
+	}
+	if m != nil {
+		if err := m.Save(ctx.Source.Options["manifest"]); err != nil {
+			return fmt.Errorf("unable to save manifest: %w", err)
+		}
+	}
+
+	var years []string
+	for _, doc := range docs {
+		years = append(years, doc.ID)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(years)))
+
+	if err := generateVulnIndex(postsDir, years, len(g.kev) > 0); err != nil {
+		return err
+	}
+	if len(g.kev) > 0 {
+		if err := generateKEVPages(postsDir, g.kev); err != nil {
+			return err
+		}
+	}
+
+	for _, year := range years {
+		generateReservedPages(ctx, year, s.clock, g.published, g.vendors, postsDir)
+	}
+	recordSkippedVendorFiles(ctx, g.vendors)
+	return nil
+}
+
+// loadEPSS returns the EPSS scores of the snapshot at path. A missing or broken
+// snapshot leaves the pages without EPSS scores, one older than epss-max-age
+// days is still used, its date being on every page.
+func (s nvdSource) loadEPSS(ctx *source.Context, path string) (map[string]EPSS, error) {
+	maxAge := -1
+	if v := ctx.Source.Options["epss-max-age"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid epss-max-age %q", v)
+		}
+		maxAge = n
+	}
+
+	snapshot, err := loadEPSSSnapshot(path)
+	switch {
+	case os.IsNotExist(err):
+		log.Printf("no EPSS snapshot at %s, pages are rendered without EPSS scores", path)
+		return nil, nil
+	case err != nil:
+		ctx.RecordError(path, report.StageLoad, err)
+		return nil, nil
+	}
+
+	now, err := time.Parse(time.RFC3339, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if age, err := snapshot.age(now); err != nil {
+		ctx.RecordError(path, report.StageLoad, fmt.Errorf("invalid snapshot date: %w", err))
+	} else if maxAge >= 0 && age > time.Duration(maxAge)*24*time.Hour {
+		log.Printf("EPSS snapshot %s is from %s, older than %d days", path, snapshot.Date, maxAge)
+	}
+	log.Printf("loaded %d EPSS scores from the %s snapshot", len(snapshot.Scores), snapshot.Date)
+	return snapshot.Scores, nil
+}
+
+func generateVulnIndex(postsDir string, years []string, knownExploited bool) error {
 	indexFile := filepath.Join(postsDir, "_index.md")
 	vulnIndex := menu.NewTopLevelMenu("Vulnerabilties", "toplevel_page", indexFile).
 		WithHeading("Vulnerabilties").
@@ -121,11 +583,7 @@ func generateVulnPages() {
 		WithCategory("vulnerabilities").
 		WithMenu("none")
 
-	years := &Years
-
-	sort.Sort(sort.Reverse(sort.StringSlice(*years)))
-
-	for _, year := range Years {
+	for _, year := range years {
 		vulnIndex.WithTile(menu.Tile{
 			Heading: year,
 			Icon:    "cve",
@@ -134,176 +592,356 @@ func generateVulnPages() {
 		},
 		)
 	}
-	if err := vulnIndex.Generate(); err != nil {
-		fail(err)
+	if knownExploited {
+		vulnIndex.WithTile(menu.Tile{
+			Heading: "Known Exploited",
+			Icon:    "cve",
+			Summary: "CVE's in the CISA Known Exploited Vulnerabilities catalog",
+			URL:     "/nvd/known-exploited/",
+		})
 	}
+	return vulnIndex.Generate()
 }
 
-func generateVulnerabilityPages(nvdDir, cweDir, postsDir, year string) {
+// nvdGeneratorVersion is bumped whenever the Go code that renders pages
+// changes what they contain, such as the Markdown of weaknesses or the CVSS
+// breakdowns, so that every page is rendered again.
+const nvdGeneratorVersion = "1"
 
-	postsDir = fmt.Sprintf("%s/%s", postsDir, year)
-	if err := os.MkdirAll(postsDir, 0755); err != nil {
-		fail(err)
-	}
+// nvdManifestVersion changes whenever the page templates, the rendering code
+// or the score precedence do, so that any of them renders every page again.
+func nvdManifestVersion(scorePrecedence string) string {
+	return manifest.HashBytes([]byte(nvdGeneratorVersion + vulnerabilityPostTemplate + rejectedPostTemplate + scorePrecedence))
+}
 
-	files, err := getAllFiles(nvdDir)
-	if err != nil {
-		log.Fatal(err)
+// cveYear is the year of a CVE ID, such as 2020 for CVE-2020-0002.
+func cveYear(id string) string {
+	parts := strings.Split(id, "-")
+	if len(parts) < 3 {
+		return ""
 	}
-	for _, file := range files {
-		bp, err := parseVulnerabilityJSONFile(file)
-		if err != nil {
-			log.Printf("unable to parse file: %s, err: %s, skipping...\n", file, err)
-			continue
-		}
+	return parts[1]
+}
 
-		_ = AddCWEInformation(&bp, cweDir)
+// vulnGenerator renders the CVE pages of an NVD run and holds what every page
+// is enriched with beyond its own NVD, CWE and vendor files.
+type vulnGenerator struct {
+	ctx         *source.Context
+	manifest    *manifest.Manifest
+	cweDir      string
+	postsDir    string
+	concurrency int
+	kev         map[string]KnownExploited
+	epss        map[string]EPSS
+	ghsa        map[string][]GHSAAdvisory
+	osv         map[string][]AffectedSoftware
+	// cweCatalog is set when the cwe input is the CWE catalogue rather than
+	// the directory of vuln-list/cwe, cweCatalogHash tells pages rendered with
+	// another version of it apart.
+	cweCatalog     *cweCatalog
+	cweCatalogHash string
+	// capec resolves the attack patterns of CWEs, capecHash tells pages
+	// rendered with another version of the catalog apart.
+	capec     map[int]AttackPattern
+	capecHash string
+	vendors   []VendorAdapter
+	// published is the CVEs of the NVD files of the years rendered, which
+	// don't get a reserved page.
+	published map[string]bool
+}
 
-		for _, vendor := range []string{"redhat", "ubuntu"} {
-			_ = AddVendorInformation(&bp, vendor, strings.ReplaceAll(nvdDir, "nvd", vendor))
-		}
+// generatePages writes a page for every CVE of the years in docs, spreading
+// the CVE files of every year over concurrency workers. The year indexes are
+// written once every page is. When the generator has a manifest, CVEs whose
+// inputs and page are unchanged since the last run are skipped.
+func (g *vulnGenerator) generatePages(docs []source.Document) error {
+	type job struct {
+		year, file string
+	}
 
-		// check if file exists first, if does then open, if not create
-		f, err := os.OpenFile(filepath.Join(postsDir, fmt.Sprintf("%s.md", bp.Title)), os.O_RDWR|os.O_CREATE, 0644)
+	var jobs []job
+	g.published = make(map[string]bool)
+	for _, doc := range docs {
+		if err := os.MkdirAll(filepath.Join(g.postsDir, doc.ID), 0755); err != nil {
+			return err
+		}
+		files, err := getAllFiles(doc.Path)
 		if err != nil {
-			log.Printf("unable to create file: %s for markdown, err: %s, skipping...\n", file, err)
-			continue
+			return fmt.Errorf("%s: %w", doc.ID, err)
 		}
+		log.Printf("generating vuln year: %s (%d files)\n", doc.ID, len(files))
+		for _, file := range files {
+			jobs = append(jobs, job{year: doc.ID, file: file})
+			g.published[strings.TrimSuffix(filepath.Base(file), ".json")] = true
+		}
+	}
 
-		customContent := GetCustomContentFromMarkdown(f.Name())
-		if customContent != "" { // truncate file if custom data was found
-			_ = f.Truncate(0)
-			_, _ = f.Seek(0, 0)
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]map[pageResult]int)
+		queue   = make(chan job)
+	)
+	for i := 0; i < g.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				result := g.generatePage(j.file, filepath.Join(g.postsDir, j.year))
+				mu.Lock()
+				if results[j.year] == nil {
+					results[j.year] = make(map[pageResult]int)
+				}
+				results[j.year][result]++
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, j := range jobs {
+		queue <- j
+	}
+	close(queue)
+	wg.Wait()
+
+	for _, doc := range docs {
+		year := doc.ID
+		if r := results[year]; r[pageSkipped]+r[pageUnchanged] > 0 {
+			log.Printf("vuln year %s: %d pages written, %d skipped, %d rendered identically, %d failed",
+				year, r[pageWritten], r[pageSkipped], r[pageUnchanged], r[pageFailed])
 		}
-		if err := VulnerabilityPostToMarkdown(bp, f, customContent); err != nil {
-			log.Printf("unable to write file: %s as markdown, err: %s, skipping...\n", file, err)
-			continue
+
+		indexFile := filepath.Join(g.postsDir, year, "_index.md")
+		if err := menu.NewTopLevelMenu(year, "avd_list", indexFile).
+			WithHeading("Vulnerabilties").
+			WithIcon("aqua").
+			WithCategory("vulnerabilities").
+			WithMenu(year).
+			WithMenuID(year).
+			WithMenuParent("vulnerabilities").
+			Generate(); err != nil {
+			return err
 		}
-		_ = f.Close()
 	}
+	return nil
+}
 
-	indexFile := filepath.Join(postsDir, "_index.md")
-	if err := menu.NewTopLevelMenu(year, "avd_list", indexFile).
-		WithHeading("Vulnerabilties").
-		WithIcon("aqua").
-		WithCategory("vulnerabilities").
-		WithMenu(year).
-		WithMenuID(year).
-		WithMenuParent("vulnerabilities").
-		Generate(); err != nil {
-		fail(err)
+type pageResult int
+
+const (
+	pageWritten pageResult = iota
+	pageSkipped
+	pageUnchanged
+	pageFailed
+)
+
+// inputHash hashes the files a CVE page is rendered from along with the
+// enrichments that don't come from a file of their own.
+func (g *vulnGenerator) inputHash(id string, files []string) (string, error) {
+	hash, err := manifest.HashFiles(files...)
+	if err != nil {
+		return "", err
+	}
+	var enrichments []any
+	if k, ok := g.kev[id]; ok {
+		enrichments = append(enrichments, k)
+	}
+	if e, ok := g.epss[id]; ok {
+		enrichments = append(enrichments, e)
+	}
+	if a, ok := g.ghsa[id]; ok {
+		enrichments = append(enrichments, a)
+	}
+	if o, ok := g.osv[id]; ok {
+		enrichments = append(enrichments, o)
+	}
+	if g.cweCatalogHash != "" {
+		enrichments = append(enrichments, g.cweCatalogHash)
+	}
+	if g.capecHash != "" {
+		enrichments = append(enrichments, g.capecHash)
 	}
+	if len(enrichments) == 0 {
+		return hash, nil
+	}
+	b, err := json.Marshal(enrichments)
+	if err != nil {
+		return "", err
+	}
+	return manifest.HashBytes(append([]byte(hash), b...)), nil
 }
 
-func generateReservedPages(year string, clock Clock, inputDir string, postsDir string) {
-	CVEMap = map[string]map[string]ReservedCVEInfo{}
-	nvdDir := fmt.Sprintf("%s/nvd/%s", inputDir, year)
-	files, _ := getAllFiles(nvdDir)
-	for _, file := range files {
-		CVEMap[strings.ReplaceAll(file, ".json", "")] = map[string]ReservedCVEInfo{
-			"nvd": {},
-		}
+func (g *vulnGenerator) generatePage(file, yearDir string) pageResult {
+	ctx := g.ctx
+	id := strings.TrimSuffix(filepath.Base(file), ".json")
+	year := cveYear(id)
+	pageFile := filepath.Join(yearDir, fmt.Sprintf("%s.md", id))
+	inputs := []string{file}
+	for _, adapter := range g.vendors {
+		files, _ := adapter.Locate(id, year)
+		inputs = append(inputs, files...)
 	}
 
-	for _, vendor := range []string{"redhat", "ubuntu"} {
-		vendorDir := fmt.Sprintf("%s/%s/%s", inputDir, vendor, year)
-		files, _ := getAllFiles(vendorDir)
-		for _, file := range files {
-			fKey := strings.ReplaceAll(filepath.Base(file), ".json", "")
-			if !existsInCVEMap(CVEMap, strings.ReplaceAll(strings.ReplaceAll(file, ".json", ""), vendor, "nvd")) {
-				if _, ok := CVEMap[fKey]; !ok {
-					CVEMap[fKey] = make(map[string]ReservedCVEInfo)
-				}
-				addReservedCVE(vendorDir, CVEMap, vendor, fKey)
+	// the CWE of a CVE is only known once it is parsed, the last run recorded
+	// it and it can't have changed unless the NVD file has
+	if e, ok := g.manifest.Get(id); ok {
+		inputHash, err := g.inputHash(id, append(inputs, e.Inputs...))
+		if err == nil && inputHash == e.InputHash {
+			if page, err := os.ReadFile(pageFile); err == nil && manifest.HashBytes(page) == e.OutputHash {
+				return pageSkipped
 			}
 		}
 	}
 
-	// cleanup NVD entries
-	for file, vendorsMap := range CVEMap {
-		for vendor := range vendorsMap {
-			if vendor == "nvd" {
-				delete(CVEMap, file)
-			}
-		}
+	bp, err := parseVulnerabilityJSONFile(file)
+	if err != nil {
+		ctx.RecordError(file, report.StageParse, err)
+		return pageFailed
+	}
+	if precedence := ctx.Source.Options["score-precedence"]; precedence != "" {
+		bp.Vulnerability.selectScores(strings.Split(precedence, ","))
 	}
 
-	for file, vendorsMap := range CVEMap {
-		f, err := os.Create(filepath.Join(postsDir, fmt.Sprintf("%s.md", filepath.Base(file))))
+	// a CVE without a CWE or vendor advisory is expected, only broken files are reported
+	switch {
+	case g.cweCatalog != nil:
+		err = addWeaknesses(&bp, g.cweCatalog.Weakness)
+	case !isCWECatalog(g.cweDir):
+		err = AddCWEInformation(&bp, g.cweDir)
+	}
+	if err != nil {
+		ctx.RecordError(file, report.StageEnrich, fmt.Errorf("cwe: %w", err))
+	}
+	for i := range bp.Vulnerability.Weaknesses {
+		bp.Vulnerability.Weaknesses[i].Info.resolveAttackPatterns(g.capec)
+	}
+
+	for _, adapter := range g.vendors {
+		info, err := adapter.Load(bp.Vulnerability.ID, year)
 		if err != nil {
-			log.Printf("unable to create file: %s for markdown, err: %s, skipping...\n", file, err)
+			if !errors.Is(err, os.ErrNotExist) {
+				ctx.RecordError(file, report.StageEnrich, fmt.Errorf("%s: %w", adapter.Name(), err))
+			}
 			continue
 		}
-		if err = ReservedPostToMarkdown(ReservedPage{
-			ID:     filepath.Base(file),
-			Date:   clock.Now(),
-			CVEMap: vendorsMap,
-		}, f); err != nil {
-			log.Println("unable to create reserved post markdown, err: ", err)
-			continue
+		bp.Vulnerability.Vendors = append(bp.Vulnerability.Vendors, info)
+		bp.Vulnerability.AffectedSoftware = append(bp.Vulnerability.AffectedSoftware, info.AffectedSoftware...)
+	}
+
+	// package versions from OSV records are more precise than those of CPEs, so are kept alongside them
+	for _, as := range g.osv[bp.Vulnerability.ID] {
+		if !slices.Contains(bp.Vulnerability.AffectedSoftware, as) {
+			bp.Vulnerability.AffectedSoftware = append(bp.Vulnerability.AffectedSoftware, as)
 		}
 	}
-}
 
-func existsInCVEMap(inputMap map[string]map[string]ReservedCVEInfo, target string) bool {
-	for k := range inputMap {
-		if target == k {
-			return true
+	if k, ok := g.kev[bp.Vulnerability.ID]; ok {
+		bp.Vulnerability.KnownExploited = &k
+	}
+	if e, ok := g.epss[bp.Vulnerability.ID]; ok {
+		bp.Vulnerability.EPSS = &e
+	}
+	bp.Vulnerability.Advisories = g.ghsa[bp.Vulnerability.ID]
+
+	for _, err := range bp.Vulnerability.checkScores() {
+		ctx.RecordError(file, report.StageValidate, err)
+	}
+
+	pageFile = filepath.Join(yearDir, fmt.Sprintf("%s.md", bp.Title))
+	var page bytes.Buffer
+	if err := VulnerabilityPostToMarkdown(bp, &page, GetCustomContentFromMarkdown(pageFile)); err != nil {
+		ctx.RecordError(file, report.StageRender, err)
+		return pageFailed
+	}
+
+	written, err := writeIfChanged(pageFile, page.Bytes())
+	if err != nil {
+		ctx.RecordError(file, report.StageWrite, err)
+		return pageFailed
+	}
+
+	// the catalogue is hashed once, as an enrichment
+	var cweInputs []string
+	if !isCWECatalog(g.cweDir) {
+		for _, w := range bp.Vulnerability.CWEs() {
+			cweInputs = append(cweInputs, filepath.Join(g.cweDir, fmt.Sprintf("%s.json", w.ID)))
 		}
 	}
-	return false
+	if inputHash, err := g.inputHash(id, append(inputs, cweInputs...)); err == nil {
+		g.manifest.Set(id, manifest.Entry{
+			Inputs:     cweInputs,
+			InputHash:  inputHash,
+			OutputHash: manifest.HashBytes(page.Bytes()),
+		})
+	}
+
+	if !written {
+		return pageUnchanged
+	}
+	return pageWritten
 }
 
-func addReservedCVE(vendorDir string, cveMap map[string]map[string]ReservedCVEInfo, vendor string, fKey string) {
-	b, _ := ioutil.ReadFile(fmt.Sprintf("%s/%s.json", vendorDir, fKey))
-
-	switch vendor {
-	case "ubuntu":
-		var ua ubuntu.Vulnerability
-		_ = json.Unmarshal(b, &ua)
-		cveMap[fKey][vendor] = ReservedCVEInfo{
-			Description: ua.Description,
-			Severity:    ua.Priority,
-		}
-		for pkg, info := range ua.Patches {
-			for release, status := range info {
-				if status.Status == "released" || status.Status == "needed" || status.Status == "ignored" || status.Status == "needs-triage" {
-					rp := cveMap[fKey][vendor]
-					as := AffectedSoftware{
-						Name:   string(pkg),
-						Vendor: fmt.Sprintf("%s/%s", vendor, release),
-					}
-					if status.Status == "needs-triage" {
-						as.StartVersion = "TBD"
-						as.EndVersion = "TBD"
-					} else {
-						as.StartVersion = status.Note
-						as.EndVersion = status.Note
-					}
-					rp.AffectedSoftwareList = append(rp.AffectedSoftwareList, as)
-					cveMap[fKey][vendor] = rp
+// generateReservedPages writes a page for every CVE of a year that a vendor
+// knows of and that isn't in published, the CVEs NVD has a file for.
+func generateReservedPages(ctx *source.Context, year string, clock Clock, published map[string]bool, adapters []VendorAdapter, postsDir string) {
+	cveMap := map[string]map[string]VendorInfo{}
+	for _, adapter := range adapters {
+		ids, err := adapter.CVEs(year)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			ctx.RecordError(adapter.Name(), report.StageLoad, err)
+		}
+		for _, fKey := range ids {
+			if published[fKey] {
+				continue
+			}
+			info, err := adapter.Load(fKey, year)
+			if err != nil {
+				if !errors.Is(err, os.ErrNotExist) {
+					ctx.RecordError(fKey, report.StageEnrich, fmt.Errorf("%s: %w", adapter.Name(), err))
 				}
+				continue
 			}
+			if _, ok := cveMap[fKey]; !ok {
+				cveMap[fKey] = make(map[string]VendorInfo)
+			}
+			cveMap[fKey][adapter.Name()] = info
 		}
-	case "redhat":
-		rh := &redhat.RedhatCVEJSON{}
-		_ = json.Unmarshal(b, &rh)
-		cveMap[fKey][vendor] = ReservedCVEInfo{
-			Description: rh.Bugzilla.Description,
-			Severity:    rh.ThreatSeverity,
-			Mitigation:  rh.Mitigation,
-		}
-		for _, release := range rh.AffectedRelease {
-			rp := cveMap[fKey][vendor]
-			rp.AffectedSoftwareList = append(rp.AffectedSoftwareList, AffectedSoftware{
-				Name:         release.ProductName,
-				Vendor:       "RedHat",
-				StartVersion: release.Package,
-				EndVersion:   release.Package,
-			})
-			cveMap[fKey][vendor] = rp
+	}
+
+	for file, vendorsMap := range cveMap {
+		pageFile := filepath.Join(postsDir, fmt.Sprintf("%s.md", filepath.Base(file)))
+		// the date is when the page was first written, so an unchanged page renders identically
+		date := reservedPageDate(pageFile)
+		if date == "" {
+			date = clock.Now()
+		}
+		var page bytes.Buffer
+		if err := ReservedPostToMarkdown(ReservedPage{
+			ID:     filepath.Base(file),
+			Date:   date,
+			CVEMap: vendorsMap,
+		}, &page); err != nil {
+			ctx.RecordError(file, report.StageRender, err)
+			continue
+		}
+		if _, err := writeIfChanged(pageFile, page.Bytes()); err != nil {
+			ctx.RecordError(file, report.StageWrite, err)
+		}
+	}
+}
+
+// reservedPageDate returns the date in the front matter of an existing
+// reserved page, empty when there is no such page.
+func reservedPageDate(pageFile string) string {
+	b, err := os.ReadFile(pageFile)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if date, ok := strings.CutPrefix(line, "date: "); ok {
+			return date
 		}
 	}
+	return ""
 }
 
 func getAllMapKeys(a interface{}) []string {
@@ -315,83 +953,44 @@ func getAllMapKeys(a interface{}) []string {
 	return strkeys
 }
 
-func AddVendorInformation(bp *VulnerabilityPost, vendor string, vendorDir string) error {
-	switch vendor {
-	case "redhat":
-		b, err := ioutil.ReadFile(filepath.Join(vendorDir, fmt.Sprintf("%s.json", bp.Vulnerability.ID)))
-		if err != nil {
-			return err
-		}
-		var p fastjson.Parser
-		v, err := p.ParseBytes(b)
-		if err != nil {
-			return err
-		}
-		bp.Vulnerability.RedHatCVSSInfo.Severity = string(v.GetStringBytes("threat_severity"))
-		bp.Vulnerability.RedHatCVSSInfo.V2Vector = string(v.GetStringBytes("cvss", "cvss_scoring_vector"))
-		bp.Vulnerability.RedHatCVSSInfo.V2Score, _ = strconv.ParseFloat(string(v.GetStringBytes("cvss", "cvss_base_score")), 64)
-		bp.Vulnerability.RedHatCVSSInfo.V3Vector = string(v.GetStringBytes("cvss3", "cvss3_scoring_vector"))
-		bp.Vulnerability.RedHatCVSSInfo.V3Score, _ = strconv.ParseFloat(string(v.GetStringBytes("cvss3", "cvss3_base_score")), 64)
-
-		affectedReleases := v.GetArray("affected_release")
-		for _, ar := range affectedReleases {
-			bp.Vulnerability.AffectedSoftware = append(bp.Vulnerability.AffectedSoftware, AffectedSoftware{
-				Name:         string(ar.GetStringBytes("product_name")),
-				Vendor:       "RedHat",
-				StartVersion: string(ar.GetStringBytes("package")),
-				EndVersion:   "*",
-			})
-		}
+// HasAffectedSoftwareStatus is whether any vendor tracks the status of the affected software.
+func (v Vulnerability) HasAffectedSoftwareStatus() bool {
+	return hasAffectedSoftwareStatus(v.AffectedSoftware)
+}
 
-	case "ubuntu":
-		b, err := ioutil.ReadFile(filepath.Join(vendorDir, fmt.Sprintf("%s.json", bp.Vulnerability.ID)))
-		if err != nil {
-			return err
-		}
-		var p fastjson.Parser
-		v, err := p.ParseBytes(b)
-		if err != nil {
-			return err
+func hasAffectedSoftwareStatus(affected []AffectedSoftware) bool {
+	for _, as := range affected {
+		if as.Status != "" || as.Notes != "" {
+			return true
 		}
-		bp.Vulnerability.UbuntuCVSSInfo.Severity = string(v.GetStringBytes("Priority"))
+	}
+	return false
+}
 
-		patchList := v.Get("Patches")
-		var patches map[string]map[string]struct {
-			Status string
-			Note   string
+// Vendor returns what a vendor knows of the CVE, nothing when it isn't one of its vendors.
+func (v Vulnerability) Vendor(name string) VendorInfo {
+	for _, info := range v.Vendors {
+		if info.Name == name {
+			return info
 		}
+	}
+	return VendorInfo{Name: name}
+}
 
-		// get all patches
-		_ = json.Unmarshal([]byte(patchList.String()), &patches)
-
-		// get all packages
-		packages := getAllMapKeys(patches)
-		sort.Slice(packages, func(i, j int) bool {
-			return packages[i] < packages[j]
-		})
-
-		for _, p := range packages {
-			dists := getAllMapKeys(patches[p])
-			sort.Slice(dists, func(i, j int) bool {
-				return dists[i] < dists[j]
-			})
-
-			for _, d := range dists {
-				status := strings.ToLower(patches[p][d].Status)
-				switch status {
-				case "needed", "pending", "ignored", "released":
-					bp.Vulnerability.AffectedSoftware = append(bp.Vulnerability.AffectedSoftware, AffectedSoftware{
-						Name:         p,
-						Vendor:       "Ubuntu",
-						StartVersion: d,
-						EndVersion:   "*",
-					})
-				}
-			}
+// frontMatterVendors have front matter of their own, even on the pages of CVEs
+// they know nothing of.
+var frontMatterVendors = []string{"redhat", "ubuntu", "debian"}
+
+// OtherVendors are the vendors of the CVE without front matter of their own,
+// only their severity is set.
+func (v Vulnerability) OtherVendors() []VendorInfo {
+	var others []VendorInfo
+	for _, info := range v.Vendors {
+		if !slices.Contains(frontMatterVendors, info.Name) {
+			others = append(others, info)
 		}
-
 	}
-	return nil
+	return others
 }
 
 func parseVulnerabilityJSONFile(fileName string) (VulnerabilityPost, error) {
@@ -408,31 +1007,21 @@ func parseVulnerabilityJSONFile(fileName string) (VulnerabilityPost, error) {
 	}
 	vuln.Description = strings.NewReplacer(`"`, ``, `\`, ``, `'`, ``).Replace(string(v.GetStringBytes("descriptions", "0", "value")))
 	vuln.ID = string(v.GetStringBytes("id"))
-	if cwe := string(v.GetStringBytes("weaknesses", "0", "description", "0", "value")); cwe != "NVD-CWE-noinfo" {
-		vuln.CWEID = cwe
-	}
-
-	for _, metricV2 := range v.GetArray("metrics", "cvssMetricV2") {
-		source := string(metricV2.GetStringBytes("source"))
-		// Save only NVD metric
-		if source == "nvd@nist.gov" {
-			vuln.CVSS.V2Score = metricV2.GetFloat64("cvssData", "baseScore")
-			vuln.CVSS.V2Vector = string(metricV2.GetStringBytes("cvssData", "vectorString"))
-			vuln.NVDSeverityV2 = string(metricV2.GetStringBytes("baseSeverity"))
+	vuln.Status = string(v.GetStringBytes("vulnStatus"))
+	for _, t := range v.GetArray("cveTags") {
+		for _, tag := range t.GetArray("tags") {
+			if string(tag.GetStringBytes()) == "disputed" {
+				vuln.Disputed = true
+			}
 		}
 	}
-
-	// Save NVD metric from v3.1,
-	// if it doesn't exist - save NVD metric from v3.0
-	for _, metricV3 := range append(v.GetArray("metrics", "cvssMetricV31"), v.GetArray("metrics", "cvssMetricV30")...) {
-		source := string(metricV3.GetStringBytes("source"))
-		// Save only NVD metric
-		if source == "nvd@nist.gov" {
-			vuln.CVSS.V3Score = metricV3.GetFloat64("cvssData", "baseScore")
-			vuln.CVSS.V3Vector = string(metricV3.GetStringBytes("cvssData", "vectorString"))
-			vuln.NVDSeverityV3 = string(metricV3.GetStringBytes("cvssData", "baseSeverity"))
-		}
+	if vuln.Rejected() {
+		vuln.Description = strings.TrimSpace(strings.TrimPrefix(vuln.Description, "** REJECT **"))
 	}
+	vuln.Weaknesses = parseWeaknesses(v)
+
+	vuln.Scores = parseScores(v.Get("metrics"))
+	vuln.selectScores(defaultScorePrecedence)
 
 	publishedDate, _ := time.Parse("2006-01-02T15:04:05", string(v.GetStringBytes("published")))
 	modifiedDate, _ := time.Parse("2006-01-02T15:04:05", string(v.GetStringBytes("lastModified")))
@@ -447,22 +1036,109 @@ func parseVulnerabilityJSONFile(fileName string) (VulnerabilityPost, error) {
 	}
 	vuln.References = refs
 
-	affectedSoftwares := v.GetArray("configurations", "0", "nodes", "0", "cpeMatch") // TODO: This logic should be improved to iterate over list of lists
-	for _, as := range affectedSoftwares {
-		uri := string(as.GetStringBytes("criteria"))
-		item, err := cpe.NewItemFromFormattedString(uri)
+	vuln.Configurations = parseConfigurations(v.GetArray("configurations"))
+	for _, config := range vuln.Configurations {
+		for _, node := range config.Nodes {
+			addAffectedSoftware(&vuln, node)
+		}
+	}
+
+	return VulnerabilityPost{
+		Layout:        "vulnerability",
+		Title:         vuln.ID,
+		By:            "NVD",
+		Date:          publishedDate.UTC().Format("2006-01-02 03:04:05 -0700"),
+		Vulnerability: vuln,
+	}, nil
+}
+
+// parseWeaknesses returns the weaknesses of a CVE in the order NVD lists them,
+// a CWE that both NVD and the CNA mapped the CVE to is listed once with both sources.
+func parseWeaknesses(v *fastjson.Value) []Weakness {
+	var weaknesses []Weakness
+	for _, w := range v.GetArray("weaknesses") {
+		source := WeaknessSource{Source: string(w.GetStringBytes("source")), Type: string(w.GetStringBytes("type"))}
+		for _, d := range w.GetArray("description") {
+			id := string(d.GetStringBytes("value"))
+			if id == "" {
+				continue
+			}
+			i := slices.IndexFunc(weaknesses, func(w Weakness) bool { return w.ID == id })
+			if i < 0 {
+				i = len(weaknesses)
+				weaknesses = append(weaknesses, Weakness{ID: id})
+			}
+			if !slices.Contains(weaknesses[i].Sources, source) {
+				weaknesses[i].Sources = append(weaknesses[i].Sources, source)
+			}
+		}
+	}
+	return weaknesses
+}
+
+func parseConfigurations(configs []*fastjson.Value) []Configuration {
+	var configurations []Configuration
+	for _, c := range configs {
+		config := Configuration{
+			Operator: string(c.GetStringBytes("operator")),
+			Negate:   c.GetBool("negate"),
+		}
+		for _, node := range c.GetArray("nodes") {
+			config.Nodes = append(config.Nodes, parseConfigurationNode(node))
+		}
+		configurations = append(configurations, config)
+	}
+	return configurations
+}
+
+func parseConfigurationNode(v *fastjson.Value) ConfigurationNode {
+	node := ConfigurationNode{
+		Operator: string(v.GetStringBytes("operator")),
+		Negate:   v.GetBool("negate"),
+	}
+	for _, m := range v.GetArray("cpeMatch") {
+		criteria := string(m.GetStringBytes("criteria"))
+		item, err := cpe.NewItemFromFormattedString(criteria)
 		if err != nil {
 			continue
 		}
+		match := CPEMatch{
+			Vulnerable:     m.GetBool("vulnerable"),
+			Criteria:       criteria,
+			Vendor:         item.Vendor().String(),
+			Name:           item.Product().String(),
+			StartIncluding: string(m.GetStringBytes("versionStartIncluding")),
+			StartExcluding: string(m.GetStringBytes("versionStartExcluding")),
+			EndIncluding:   string(m.GetStringBytes("versionEndIncluding")),
+			EndExcluding:   string(m.GetStringBytes("versionEndExcluding")),
+		}
+		if version := item.Version().String(); version != "*" && version != "-" {
+			match.Version = version
+			if update := item.Update().String(); update != "*" && update != "-" {
+				match.Version += "-" + update
+			}
+		}
+		node.Matches = append(node.Matches, match)
+	}
+	for _, child := range v.GetArray("children") {
+		node.Children = append(node.Children, parseConfigurationNode(child))
+	}
+	return node
+}
 
-		startVersion := detectVersion(string(as.GetStringBytes("versionStartIncluding")), string(as.GetStringBytes("versionStartExcluding")), item)
-		endVersion := detectVersion(string(as.GetStringBytes("versionEndIncluding")), string(as.GetStringBytes("versionEndExcluding")), item)
-
+// addAffectedSoftware lists the vulnerable matches of a node, platforms are
+// only shown in the applicability statements.
+func addAffectedSoftware(vuln *Vulnerability, node ConfigurationNode) {
+	for _, m := range node.Matches {
+		if !m.Vulnerable {
+			continue
+		}
+		item, _ := cpe.NewItemFromFormattedString(m.Criteria)
 		affectedSoftware := AffectedSoftware{
-			Name:         item.Product().String(),
-			Vendor:       item.Vendor().String(),
-			StartVersion: startVersion,
-			EndVersion:   endVersion,
+			Name:         m.Name,
+			Vendor:       m.Vendor,
+			StartVersion: detectVersion(m.StartIncluding, m.StartExcluding, item),
+			EndVersion:   detectVersion(m.EndIncluding, m.EndExcluding, item),
 		}
 
 		// Avoid duplicates
@@ -470,14 +1146,143 @@ func parseVulnerabilityJSONFile(fileName string) (VulnerabilityPost, error) {
 			vuln.AffectedSoftware = append(vuln.AffectedSoftware, affectedSoftware)
 		}
 	}
+	for _, child := range node.Children {
+		addAffectedSoftware(vuln, child)
+	}
+}
 
-	return VulnerabilityPost{
-		Layout:        "vulnerability",
-		Title:         vuln.ID,
-		By:            "NVD",
-		Date:          publishedDate.UTC().Format("2006-01-02 03:04:05 -0700"),
-		Vulnerability: vuln,
-	}, nil
+// Versions describes the versions a match covers, such as "from 1.0 (including) up to 2.0 (excluding)".
+func (m CPEMatch) Versions() string {
+	if m.Version != "" {
+		return "version " + m.Version
+	}
+	var parts []string
+	switch {
+	case m.StartIncluding != "":
+		parts = append(parts, fmt.Sprintf("from %s (including)", m.StartIncluding))
+	case m.StartExcluding != "":
+		parts = append(parts, fmt.Sprintf("from %s (excluding)", m.StartExcluding))
+	}
+	switch {
+	case m.EndIncluding != "":
+		parts = append(parts, fmt.Sprintf("up to %s (including)", m.EndIncluding))
+	case m.EndExcluding != "":
+		parts = append(parts, fmt.Sprintf("up to %s (excluding)", m.EndExcluding))
+	}
+	if len(parts) == 0 {
+		return "all versions"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Quantifier describes how the matches and children of a node combine, such as "any of".
+func (n ConfigurationNode) Quantifier() string {
+	q := "any of"
+	switch {
+	case n.Operator == "AND" && n.Negate:
+		q = "not all of"
+	case n.Operator == "AND":
+		q = "all of"
+	case n.Negate:
+		q = "none of"
+	}
+	if n.IsPlatform() {
+		return "running on or with " + q
+	}
+	return q
+}
+
+// Quantifier describes how the nodes of a configuration combine, it is empty
+// when there is a single node.
+func (c Configuration) Quantifier() string {
+	switch {
+	case c.Operator == "AND" && c.Negate:
+		return "not all of"
+	case c.Operator == "AND":
+		return "all of"
+	case c.Negate:
+		return "none of"
+	case len(c.Nodes) > 1:
+		return "any of"
+	}
+	return ""
+}
+
+// Markdown renders the nodes of a configuration as a nested list of
+// applicability statements.
+func (c Configuration) Markdown() string {
+	var b strings.Builder
+	for _, node := range c.Nodes {
+		writeConfigurationNode(&b, node, 0)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeConfigurationNode(b *strings.Builder, node ConfigurationNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	fmt.Fprintf(b, "%s- %s\n", indent, capitalize(node.Quantifier()))
+	for _, m := range node.Matches {
+		fmt.Fprintf(b, "%s  - %s %s, %s", indent, capitalize(m.Vendor), capitalize(m.Name), m.Versions())
+		if !m.Vulnerable && !node.IsPlatform() {
+			b.WriteString(" (platform)")
+		}
+		b.WriteString("\n")
+	}
+	for _, child := range node.Children {
+		writeConfigurationNode(b, child, depth+1)
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// IsPlatform reports whether none of the node's matches are vulnerable, such
+// as the hardware vulnerable firmware runs on.
+func (n ConfigurationNode) IsPlatform() bool {
+	for _, m := range n.Matches {
+		if m.Vulnerable {
+			return false
+		}
+	}
+	for _, child := range n.Children {
+		if !child.IsPlatform() {
+			return false
+		}
+	}
+	return len(n.Matches)+len(n.Children) > 0
+}
+
+// HasConditions reports whether the affected software table alone would
+// misrepresent the configurations, because some combine products, negate them
+// or name platforms that aren't vulnerable themselves.
+func (v Vulnerability) HasConditions() bool {
+	for _, config := range v.Configurations {
+		if config.Negate || len(config.Nodes) > 1 {
+			return true
+		}
+		for _, node := range config.Nodes {
+			if node.hasConditions() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (n ConfigurationNode) hasConditions() bool {
+	if n.Negate || n.Operator == "AND" || len(n.Children) > 0 {
+		return true
+	}
+	for _, m := range n.Matches {
+		if !m.Vulnerable {
+			return true
+		}
+	}
+	return false
 }
 
 func detectVersion(includeVersion, excludeVersion string, item *cpe.Item) string {
@@ -500,20 +1305,26 @@ func detectVersion(includeVersion, excludeVersion string, item *cpe.Item) string
 	return version
 }
 
-func VulnerabilityPostToMarkdown(blog VulnerabilityPost, outputFile *os.File, customContent string) error {
-	t := template.Must(template.New("blog").Funcs(gtf.GtfTextFuncMap).Parse(vulnerabilityPostTemplate))
+func VulnerabilityPostToMarkdown(blog VulnerabilityPost, outputFile io.Writer, customContent string) error {
+	postTemplate := vulnerabilityPostTemplate
+	if blog.Vulnerability.Rejected() {
+		postTemplate = rejectedPostTemplate
+	}
+	t := template.Must(template.New("blog").Funcs(gtf.GtfTextFuncMap).Funcs(template.FuncMap{
+		"inc": func(i int) int { return i + 1 },
+	}).Parse(postTemplate))
 	err := t.Execute(outputFile, blog)
 	if err != nil {
 		return err
 	}
 
 	if customContent != "" {
-		_, _ = outputFile.WriteString("\n" + customContent)
+		_, _ = io.WriteString(outputFile, "\n"+customContent)
 	}
 	return nil
 }
 
-func ReservedPostToMarkdown(rpi ReservedPage, outputFile *os.File) error {
+func ReservedPostToMarkdown(rpi ReservedPage, outputFile io.Writer) error {
 	t := template.Must(template.New("reservedCVEPost").Funcs(gtf.GtfTextFuncMap).Parse(reservedPostTemplate))
 	err := t.Execute(outputFile, rpi)
 	if err != nil {
@@ -539,7 +1350,8 @@ func GetCustomContentFromMarkdown(fileName string) string {
 const vulnerabilityPostTemplate = `---
 title: "{{.Title}}"
 aliases: [
-	"/nvd/{{ lower .Title}}"
+	"/nvd/{{ lower .Title}}"{{range .Vulnerability.Advisories}},
+	"/ghsa/{{lower .ID}}"{{end}}
 ]
 
 shortName: "{{.ShortName}}"
@@ -551,70 +1363,227 @@ avd_page_type: nvd_page
 
 date_published: {{.Vulnerability.Dates.Published}}
 date_modified: {{.Vulnerability.Dates.Modified}}
+{{- with .Vulnerability.Status}}
+vuln_status: "{{.}}"
+{{- end}}
+{{- if .Vulnerability.Disputed}}
+disputed: true
+{{- end}}
 
 header_subtitle: "{{.ShortName}}"
 
 sidebar_additional_info_nvd: "https://nvd.nist.gov/vuln/detail/{{.Title}}"
-sidebar_additional_info_cwe: "https://cwe.mitre.org/data/definitions/{{.Vulnerability.CWEID | replace "CWE-"}}.html"
+{{- with .Vulnerability.CWEs}}
+sidebar_additional_info_cwe: "https://cwe.mitre.org/data/definitions/{{(index . 0).ID | replace "CWE-"}}.html"
+{{- end}}
+
+cvss_nvd_v4_vector: "{{.Vulnerability.CVSS.V4Vector | default "N/A"}}"
+cvss_nvd_v4_score: "{{.Vulnerability.CVSS.V4Score}}"
+cvss_nvd_v4_severity: "{{.Vulnerability.NVDSeverityV4 | upper | default "N/A"}}"
+cvss_nvd_v4_source: "{{.Vulnerability.CVSS.V4Source | default "N/A"}}"
 
 cvss_nvd_v3_vector: "{{.Vulnerability.CVSS.V3Vector | default "N/A"}}"
 cvss_nvd_v3_score: "{{.Vulnerability.CVSS.V3Score}}"
 cvss_nvd_v3_severity: "{{.Vulnerability.NVDSeverityV3 | upper | default "N/A"}}"
+cvss_nvd_v3_source: "{{.Vulnerability.CVSS.V3Source | default "N/A"}}"
 
 cvss_nvd_v2_vector: "{{.Vulnerability.CVSS.V2Vector | default "N/A"}}"
 cvss_nvd_v2_score: "{{.Vulnerability.CVSS.V2Score}}"
 cvss_nvd_v2_severity: "{{.Vulnerability.NVDSeverityV2 | upper | default "N/A"}}"
+cvss_nvd_v2_source: "{{.Vulnerability.CVSS.V2Source | default "N/A"}}"
 
-redhat_v2_vector: "{{.Vulnerability.RedHatCVSSInfo.CVSS.V2Vector | default "N/A"}}"
-redhat_v2_score: "{{.Vulnerability.RedHatCVSSInfo.CVSS.V2Score}}"
-redhat_v2_severity: "{{.Vulnerability.RedHatCVSSInfo.Severity | upper | default "N/A" }}"
+redhat_v2_vector: "{{(.Vulnerability.Vendor "redhat").CVSS.V2Vector | default "N/A"}}"
+redhat_v2_score: "{{(.Vulnerability.Vendor "redhat").CVSS.V2Score}}"
+redhat_v2_severity: "{{(.Vulnerability.Vendor "redhat").Severity | upper | default "N/A" }}"
 
-redhat_v3_vector: "{{.Vulnerability.RedHatCVSSInfo.CVSS.V3Vector | default "N/A"}}"
-redhat_v3_score: "{{.Vulnerability.RedHatCVSSInfo.CVSS.V3Score}}"
-redhat_v3_severity: "{{.Vulnerability.RedHatCVSSInfo.Severity | upper | default "N/A" }}"
+redhat_v3_vector: "{{(.Vulnerability.Vendor "redhat").CVSS.V3Vector | default "N/A"}}"
+redhat_v3_score: "{{(.Vulnerability.Vendor "redhat").CVSS.V3Score}}"
+redhat_v3_severity: "{{(.Vulnerability.Vendor "redhat").Severity | upper | default "N/A" }}"
 
 ubuntu_vector: "N/A"
 ubuntu_score: "N/A"
-ubuntu_severity: "{{.Vulnerability.UbuntuCVSSInfo.Severity | upper | default "N/A"}}"
+ubuntu_severity: "{{(.Vulnerability.Vendor "ubuntu").Severity | upper | default "N/A"}}"
+
+debian_severity: "{{(.Vulnerability.Vendor "debian").Severity | upper | default "N/A"}}"
+{{- range .Vulnerability.OtherVendors}}
+{{.Name}}_severity: "{{.Severity | upper | default "N/A"}}"
+{{- end}}
+{{- with .Vulnerability.KnownExploited}}
+
+known_exploited: true
+kev_date_added: {{.DateAdded}}
+kev_due_date: {{.DueDate}}
+kev_ransomware_use: "{{.KnownRansomwareCampaignUse}}"
+{{- end}}
+{{- with .Vulnerability.EPSS}}
+
+epss_score: {{printf "%.5f" .Score}}
+epss_percentile: {{printf "%.5f" .Percentile}}
+epss_date: {{.Date}}
+{{- end}}
 
 ---
 
 {{.Vulnerability.Description}}
 
+{{- if .Vulnerability.Disputed}}
+### Disputed
+The validity of this CVE is disputed, by the vendor of the affected software or by others. See the references for the discussion.
+{{end}}
+
+{{- with .Vulnerability.KnownExploited}}
+### Known Exploited {.with_icon .weakness}
+This vulnerability is actively exploited and is listed in the [CISA Known Exploited Vulnerabilities catalog](https://www.cisa.gov/known-exploited-vulnerabilities-catalog){{with .VulnerabilityName}} as *{{.}}*{{end}}.
+
+| Date Added | Due Date | Known Ransomware Campaign Use |
+| ------------- |-------------|-----|
+| {{.DateAdded}} | {{.DueDate}} | {{.KnownRansomwareCampaignUse}} |
+
+**Required Action:** {{.RequiredAction}}
+{{- with .Notes}}
+
+**Notes:** {{.}}
+{{- end}}
+{{end}}
+
 
-{{- if .Vulnerability.CWEInfo.Description}}
+{{- range $w := .Vulnerability.Weaknesses}}
 ### Weakness {.with_icon .weakness}
-{{.Vulnerability.CWEInfo.Description}}
+{{with $w.Link}}[{{$w.Title}}]({{.}}){{else}}{{$w.Title}}{{end}}
+{{with $w.Description}}
+{{.}}
+{{end}}{{with $w.MappedBy}}
+Mapped by {{.}}
+{{end}}
+{{- end}}
+
+{{- if .Vulnerability.Scores}}
+### CVSS Scores
+| Source | Type | Version | Vector | Score | Severity |
+| ------------- |-------------|-----|----|----|----|{{range $s := .Vulnerability.Scores}}
+| {{$s.Source}} | {{$s.Type}} | {{$s.Version}} | {{$s.Vector}} | {{$s.Score}} | {{$s.Severity | upper}} |{{end}}
+{{end}}
+
+{{- with .Vulnerability.EPSS}}
+### EPSS
+The [Exploit Prediction Scoring System](https://www.first.org/epss/) estimates the probability of this vulnerability being exploited in the next 30 days.
+
+| Score | Percentile | Snapshot Date |
+| ------------- |-------------|-----|
+| {{printf "%.5f" .Score}} | {{printf "%.5f" .Percentile}} | {{.Date}} |
+{{end}}
+
+{{- range $b := .Vulnerability.VectorBreakdowns}}
+### {{$b.Title}}
+| Metric | Value |
+| ------------- |-------------|{{range $m := $b.Metrics}}
+| {{$m.Name}} ({{$m.Abbreviation}}) | {{$m.Meaning}} |{{end}}
 {{end}}
 
 {{- if .Vulnerability.AffectedSoftware}}
 ### Affected Software {.with_icon .affected_software}
+{{- if .Vulnerability.HasAffectedSoftwareStatus}}
+| Name | Vendor           | Start Version | End Version | Status | Notes |
+| ------------- |-------------|-----|----|----|----|{{range $s := .Vulnerability.AffectedSoftware}}
+| {{$s.Name | capfirst}} | {{$s.Vendor | capfirst }} | {{$s.StartVersion}} | {{$s.EndVersion}} | {{$s.Status}} | {{$s.Notes}} |{{end}}
+{{- else}}
 | Name | Vendor           | Start Version | End Version |
 | ------------- |-------------|-----|----|{{range $s := .Vulnerability.AffectedSoftware}}
 | {{$s.Name | capfirst}} | {{$s.Vendor | capfirst }} | {{$s.StartVersion}} | {{$s.EndVersion}}|{{end}}
+{{- end}}
 {{end}}
 
-{{- if .Vulnerability.CWEInfo.ExtendedDescription}}
-### Extended Description{{range $ed := .Vulnerability.CWEInfo.ExtendedDescription}}
-{{$ed}}{{end}}
+{{- range $v := .Vulnerability.Vendors}}{{if $v.Releases}}
+### {{$v.Title}} Releases {.with_icon .affected_software}
+| Package | Release | Status | Fixed Version | Priority | Notes |
+| ------------- |-------------|-----|----|----|----|{{range $r := $v.Releases}}
+| {{$r.Package}} | {{$r.Release}} | {{$r.Badge}} | {{$r.FixedVersion}} | {{$r.Priority}} | {{$r.Note}} |{{end}}
+{{end}}{{end}}
+
+{{- range $v := .Vulnerability.Vendors}}{{with $v.Mitigation}}
+### {{$v.Title}} Mitigation {.with_icon .mitigations}
+{{.}}
+{{end}}{{end}}
+
+{{- if .Vulnerability.HasConditions}}
+### Applicability {.with_icon .affected_software}
+{{- range $i, $c := .Vulnerability.Configurations}}
+
+**Configuration {{inc $i}}**{{with $c.Quantifier}}, {{.}} the following{{end}}:
+{{$c.Markdown}}
+{{- end}}
 {{end}}
 
-{{- if .Vulnerability.CWEInfo.PotentialMitigations.Mitigation}}
-### Potential Mitigations {.with_icon .mitigations}{{range $mitigation := .Vulnerability.CWEInfo.PotentialMitigations.Mitigation}}
-{{- if $mitigation.Description}}{{range $d := $mitigation.Description}}
-- {{$d}}{{end}}{{end}}{{end}}
+{{- if .Vulnerability.Advisories}}
+### GitHub Security Advisories {.with_icon .affected_software}
+| Advisory | Ecosystem | Package | Vulnerable Versions | First Patched Version |
+| ------------- |-------------|-----|----|----|{{range $a := .Vulnerability.Advisories}}{{range $p := $a.Packages}}{{range $r := $p.Ranges}}
+| [{{$a.ID}}]({{$a.URL}}) | {{$p.EcosystemName}} | {{$p.Name}} | {{$r.Vulnerable}} | {{$r.FirstPatched | default "None"}} |{{end}}{{end}}{{end}}
 {{end}}
 
-{{- if .Vulnerability.CWEInfo.RelatedAttackPatterns.RelatedAttackPattern}}
-### Related Attack Patterns {.with_icon .related_patterns}{{range $attack := .Vulnerability.CWEInfo.RelatedAttackPatterns.RelatedAttackPattern}}
-- https://cwe.mitre.org/data/definitions/{{$attack.CAPECID}}.html{{end}}
-{{end}}
+{{- $cwes := .Vulnerability.LoadedCWEs}}
+{{- $several := gt (len $cwes) 1}}
+{{- range $w := $cwes}}{{if $w.Info.ExtendedDescription}}
+### Extended Description{{if $several}} of {{$w.ID}}{{end}}{{range $ed := $w.Info.ExtendedDescription}}
+{{$ed}}{{end}}
+{{end}}{{end}}
+
+{{- range $w := $cwes}}{{with $w.Info.ConsequenceMatrix.Markdown}}
+### Common Consequences{{if $several}} of {{$w.ID}}{{end}}
+{{.}}
+{{end}}{{end}}
+
+{{- range $w := $cwes}}{{with $w.Info.MitigationsByPhase}}
+### Potential Mitigations{{if $several}} for {{$w.ID}}{{end}} {.with_icon .mitigations}{{range $p := .}}
+
+#### {{$p.Phase}}{{range $m := $p.Mitigations}}
+{{$m.Markdown}}{{end}}{{end}}
+{{end}}{{end}}
+
+{{- range $w := $cwes}}{{if $w.Info.RelatedAttackPatterns.RelatedAttackPattern}}
+### Related Attack Patterns{{if $several}} of {{$w.ID}}{{end}} {.with_icon .related_patterns}{{range $attack := $w.Info.RelatedAttackPatterns.RelatedAttackPattern}}
+{{$attack.Markdown}}{{end}}
+{{end}}{{end}}
 
 ### References  {.with_icon .references}{{range $element := .Vulnerability.References}}
 - {{$element}}{{end}}
 
 <!--- Add Aqua content below --->`
 
+// rejectedPostTemplate renders CVEs NVD rejected, which are left out of the
+// year listings and the search index by the theme.
+const rejectedPostTemplate = `---
+title: "{{.Title}}"
+aliases: [
+	"/nvd/{{ lower .Title}}"
+]
+
+date: {{.Date}}
+category: vulnerabilities
+draft: false
+
+avd_page_type: rejected_page
+
+date_published: {{.Vulnerability.Dates.Published}}
+date_modified: {{.Vulnerability.Dates.Modified}}
+
+vuln_status: "{{.Vulnerability.Status}}"
+
+sidebar_additional_info_nvd: "https://nvd.nist.gov/vuln/detail/{{.Title}}"
+
+---
+
+This CVE has been __REJECTED__ by NVD. The CVE ID is not in use, because it was withdrawn by its CNA, is a duplicate of another CVE or turned out not to be a vulnerability.
+
+{{.Vulnerability.Description}}
+{{- if .Vulnerability.References}}
+
+### References  {.with_icon .references}{{range $element := .Vulnerability.References}}
+- {{$element}}{{end}}
+{{- end}}
+
+<!--- Add Aqua content below --->`
+
 const reservedPostTemplate = `---
 title: "{{.ID}}"
 date: {{.Date}}
@@ -642,10 +1611,22 @@ Any vendor information available is shown as below.
 #### Mitigation
 {{ $reservedCVEInfo.Mitigation }}
 {{end}}
-{{if $reservedCVEInfo.AffectedSoftwareList}}
+{{if $reservedCVEInfo.AffectedSoftware}}
 #### Affected Software List
-| Name | Vendor           | Version |
-| ------------- |-------------|-----|{{range $s := $reservedCVEInfo.AffectedSoftwareList}}
-| {{$s.Name | capfirst}} | {{$s.Vendor | capfirst }} | {{$s.StartVersion}}|{{end}}
+{{- if $reservedCVEInfo.HasAffectedSoftwareStatus}}
+| Name | Vendor           | Start Version | End Version | Status | Notes |
+| ------------- |-------------|-----|----|----|----|{{range $s := $reservedCVEInfo.AffectedSoftware}}
+| {{$s.Name | capfirst}} | {{$s.Vendor | capfirst }} | {{$s.StartVersion}} | {{$s.EndVersion}} | {{$s.Status}} | {{$s.Notes}} |{{end}}
+{{- else}}
+| Name | Vendor           | Start Version | End Version |
+| ------------- |-------------|-----|----|{{range $s := $reservedCVEInfo.AffectedSoftware}}
+| {{$s.Name | capfirst}} | {{$s.Vendor | capfirst }} | {{$s.StartVersion}} | {{$s.EndVersion}}|{{end}}
+{{- end}}
+{{end}}
+{{- if $reservedCVEInfo.Releases}}
+#### Releases
+| Package | Release | Status | Fixed Version | Priority | Notes |
+| ------------- |-------------|-----|----|----|----|{{range $r := $reservedCVEInfo.Releases}}
+| {{$r.Package}} | {{$r.Release}} | {{$r.Badge}} | {{$r.FixedVersion}} | {{$r.Priority}} | {{$r.Note}} |{{end}}
 {{end}}
 {{end}}`
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aquasecurity/avd-generator/manifest"
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -25,6 +30,8 @@ func TestParseVulnerabilityJSONFile(t *testing.T) {
 				Date:   "2020-01-08 07:15:12 +0000",
 				Vulnerability: Vulnerability{
 					ID:          "CVE-2020-0001",
+					Status:      "Analyzed",
+					Weaknesses:  []Weakness{{ID: "NVD-CWE-noinfo", Sources: []WeaknessSource{{Source: "nvd@nist.gov", Type: "Primary"}}}},
 					Description: "In getProcessRecordLocked of ActivityManagerService.java isolated apps are not handled correctly. This could lead to local escalation of privilege with no additional execution privileges needed. User interaction is not needed for exploitation. Product: Android Versions: Android-8.0, Android-8.1, Android-9, and Android-10 Android ID: A-140055304",
 					References: []string{
 						"https://source.android.com/security/bulletin/2020-01-01",
@@ -32,8 +39,10 @@ func TestParseVulnerabilityJSONFile(t *testing.T) {
 					CVSS: CVSS{
 						V2Vector: "AV:L/AC:L/Au:N/C:C/I:C/A:C",
 						V2Score:  7.2,
+						V2Source: "nvd@nist.gov",
 						V3Vector: "CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H",
 						V3Score:  7.8,
+						V3Source: "nvd@nist.gov",
 					},
 					Dates: Dates{
 						Published: "2020-01-08 07:15:12 +0000",
@@ -41,6 +50,10 @@ func TestParseVulnerabilityJSONFile(t *testing.T) {
 					},
 					NVDSeverityV2: "HIGH",
 					NVDSeverityV3: "HIGH",
+					Scores: []CVSSScore{
+						{Source: "nvd@nist.gov", Type: "Primary", Version: "3.1", Vector: "CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H", Score: 7.8, Severity: "HIGH"},
+						{Source: "nvd@nist.gov", Type: "Primary", Version: "2.0", Vector: "AV:L/AC:L/Au:N/C:C/I:C/A:C", Score: 7.2, Severity: "HIGH"},
+					},
 					AffectedSoftware: []AffectedSoftware{
 						{
 							Name:         "android",
@@ -67,6 +80,15 @@ func TestParseVulnerabilityJSONFile(t *testing.T) {
 							EndVersion:   "10.0 (including)",
 						},
 					},
+					Configurations: []Configuration{{Nodes: []ConfigurationNode{{
+						Operator: "OR",
+						Matches: []CPEMatch{
+							{Vulnerable: true, Criteria: "cpe:2.3:o:google:android:8.0:*:*:*:*:*:*:*", Vendor: "google", Name: "android", Version: "8.0"},
+							{Vulnerable: true, Criteria: "cpe:2.3:o:google:android:8.1:-:*:*:*:*:*:*", Vendor: "google", Name: "android", Version: "8.1"},
+							{Vulnerable: true, Criteria: "cpe:2.3:o:google:android:9.0:beta1:*:*:*:*:*:*", Vendor: "google", Name: "android", Version: "9.0-beta1"},
+							{Vulnerable: true, Criteria: "cpe:2.3:o:google:android:10.0:*:*:*:*:*:*:*", Vendor: "google", Name: "android", Version: "10.0"},
+						},
+					}}}},
 				},
 			},
 		},
@@ -78,8 +100,11 @@ func TestParseVulnerabilityJSONFile(t *testing.T) {
 				By:     "NVD",
 				Date:   "2020-05-13 01:15:12 +0000",
 				Vulnerability: Vulnerability{
-					ID:          "CVE-2020-11932",
-					CWEID:       "CWE-532",
+					ID:     "CVE-2020-11932",
+					Status: "Modified",
+					Weaknesses: []Weakness{
+						{ID: "CWE-532", Sources: []WeaknessSource{{Source: "nvd@nist.gov", Type: "Primary"}, {Source: "security@ubuntu.com", Type: "Secondary"}}},
+					},
 					Description: "It was discovered that the Subiquity installer for Ubuntu Server logged the LUKS full disk encryption password if one was entered.",
 					References: []string{
 						"https://aliceandbob.company/the-human-factor-in-an-economy-of-scale",
@@ -88,8 +113,10 @@ func TestParseVulnerabilityJSONFile(t *testing.T) {
 					CVSS: CVSS{
 						V2Vector: "AV:L/AC:L/Au:N/C:P/I:N/A:N",
 						V2Score:  2.1,
+						V2Source: "nvd@nist.gov",
 						V3Vector: "CVSS:3.1/AV:L/AC:L/PR:H/UI:N/S:U/C:L/I:N/A:N",
 						V3Score:  2.3,
+						V3Source: "nvd@nist.gov",
 					},
 					Dates: Dates{
 						Published: "2020-05-13 01:15:12 +0000",
@@ -97,6 +124,11 @@ func TestParseVulnerabilityJSONFile(t *testing.T) {
 					},
 					NVDSeverityV2: "LOW",
 					NVDSeverityV3: "LOW",
+					Scores: []CVSSScore{
+						{Source: "nvd@nist.gov", Type: "Primary", Version: "3.1", Vector: "CVSS:3.1/AV:L/AC:L/PR:H/UI:N/S:U/C:L/I:N/A:N", Score: 2.3, Severity: "LOW"},
+						{Source: "security@ubuntu.com", Type: "Secondary", Version: "3.1", Vector: "CVSS:3.1/AV:L/AC:L/PR:H/UI:N/S:U/C:L/I:N/A:N", Score: 2.3, Severity: "LOW"},
+						{Source: "nvd@nist.gov", Type: "Primary", Version: "2.0", Vector: "AV:L/AC:L/Au:N/C:P/I:N/A:N", Score: 2.1, Severity: "LOW"},
+					},
 					AffectedSoftware: []AffectedSoftware{
 						{
 							Name:         "subiquity",
@@ -105,6 +137,12 @@ func TestParseVulnerabilityJSONFile(t *testing.T) {
 							EndVersion:   "20.05.2 (excluding)",
 						},
 					},
+					Configurations: []Configuration{{Nodes: []ConfigurationNode{{
+						Operator: "OR",
+						Matches: []CPEMatch{
+							{Vulnerable: true, Criteria: "cpe:2.3:a:canonical:subiquity:*:*:*:*:*:*:*:*", Vendor: "canonical", Name: "subiquity", EndExcluding: "20.05.2"},
+						},
+					}}}},
 				},
 			},
 		},
@@ -116,8 +154,12 @@ func TestParseVulnerabilityJSONFile(t *testing.T) {
 				By:     "NVD",
 				Date:   "2022-08-19 09:15:08 +0000",
 				Vulnerability: Vulnerability{
-					ID:          "CVE-2022-2788",
-					CWEID:       "CWE-22",
+					ID:     "CVE-2022-2788",
+					Status: "Analyzed",
+					Weaknesses: []Weakness{
+						{ID: "CWE-22", Sources: []WeaknessSource{{Source: "nvd@nist.gov", Type: "Primary"}}},
+						{ID: "CWE-29", Sources: []WeaknessSource{{Source: "ics-cert@hq.dhs.gov", Type: "Secondary"}}},
+					},
 					Description: "Emerson Electrics Proficy Machine Edition Version 9.80 and prior is vulnerable to CWE-29 Path Traversal: ..Filename, also known as a ZipSlip attack, through an upload procedure which enables attackers to implant a malicious .BLZ file on the PLC. The file can transfer through the engineering station onto Windows in a way that executes the malicious code.",
 					References: []string{
 						"https://www.cisa.gov/uscert/ics/advisories/icsa-22-228-06",
@@ -125,12 +167,17 @@ func TestParseVulnerabilityJSONFile(t *testing.T) {
 					CVSS: CVSS{
 						V3Vector: "CVSS:3.1/AV:L/AC:L/PR:L/UI:R/S:U/C:H/I:H/A:H",
 						V3Score:  7.3,
+						V3Source: "nvd@nist.gov",
 					},
 					Dates: Dates{
 						Published: "2022-08-19 09:15:08 +0000",
 						Modified:  "2023-06-28 02:25:03 +0000",
 					},
 					NVDSeverityV3: "HIGH",
+					Scores: []CVSSScore{
+						{Source: "nvd@nist.gov", Type: "Primary", Version: "3.1", Vector: "CVSS:3.1/AV:L/AC:L/PR:L/UI:R/S:U/C:H/I:H/A:H", Score: 7.3, Severity: "HIGH"},
+						{Source: "ics-cert@hq.dhs.gov", Type: "Secondary", Version: "3.1", Vector: "CVSS:3.1/AV:L/AC:L/PR:L/UI:R/S:U/C:N/I:L/A:L", Score: 3.9, Severity: "LOW"},
+					},
 					AffectedSoftware: []AffectedSoftware{
 						{
 							Name:         "electric's_proficy",
@@ -139,6 +186,12 @@ func TestParseVulnerabilityJSONFile(t *testing.T) {
 							EndVersion:   "9.80 (including)",
 						},
 					},
+					Configurations: []Configuration{{Nodes: []ConfigurationNode{{
+						Operator: "OR",
+						Matches: []CPEMatch{
+							{Vulnerable: true, Criteria: `cpe:2.3:a:emerson:electric\'s_proficy:*:*:*:*:machine:*:*:*`, Vendor: "emerson", Name: "electric's_proficy", EndIncluding: "9.80"},
+						},
+					}}}},
 				},
 			},
 		},
@@ -166,7 +219,8 @@ func TestVulnerabilityPostToMarkdown(t *testing.T) {
 				Date:   "2020-05-13 12:01:15 +0000",
 				Vulnerability: Vulnerability{
 					ID:          "CVE-2020-11932",
-					CWEID:       "CWE-532",
+					Status:      "Modified",
+					Weaknesses:  []Weakness{{ID: "CWE-532", Sources: []WeaknessSource{{Source: "nvd@nist.gov", Type: "Primary"}}}},
 					Description: "It was discovered that the Subiquity installer for Ubuntu Server logged the LUKS full disk encryption password if one was entered.",
 					References: []string{
 						"https://github.com/CanonicalLtd/subiquity/commit/7db70650feaf513d7fb6f1ca07f2d670a0890613",
@@ -208,19 +262,27 @@ avd_page_type: nvd_page
 
 date_published: 2020-05-13T00:01Z
 date_modified: 2020-05-18T00:17Z
+vuln_status: "Modified"
 
 header_subtitle: ""
 
 sidebar_additional_info_nvd: "https://nvd.nist.gov/vuln/detail/CVE-2020-11932"
 sidebar_additional_info_cwe: "https://cwe.mitre.org/data/definitions/532.html"
 
+cvss_nvd_v4_vector: "N/A"
+cvss_nvd_v4_score: "0"
+cvss_nvd_v4_severity: "N/A"
+cvss_nvd_v4_source: "N/A"
+
 cvss_nvd_v3_vector: "CVSS:3.1/AV:L/AC:L/PR:H/UI:N/S:U/C:L/I:N/A:N"
 cvss_nvd_v3_score: "2.3"
 cvss_nvd_v3_severity: "LOW"
+cvss_nvd_v3_source: "N/A"
 
 cvss_nvd_v2_vector: "AV:L/AC:L/Au:N/C:P/I:N/A:N"
 cvss_nvd_v2_score: "2.1"
 cvss_nvd_v2_severity: "HIGH"
+cvss_nvd_v2_source: "N/A"
 
 redhat_v2_vector: "N/A"
 redhat_v2_score: "0"
@@ -234,9 +296,38 @@ ubuntu_vector: "N/A"
 ubuntu_score: "N/A"
 ubuntu_severity: "N/A"
 
+debian_severity: "N/A"
+
 ---
 
 It was discovered that the Subiquity installer for Ubuntu Server logged the LUKS full disk encryption password if one was entered.
+### Weakness {.with_icon .weakness}
+[CWE-532](https://cwe.mitre.org/data/definitions/532.html)
+
+Mapped by nvd@nist.gov (Primary)
+
+### CVSS v3.1 Vector
+| Metric | Value |
+| ------------- |-------------|
+| Attack Vector (AV) | Local |
+| Attack Complexity (AC) | Low |
+| Privileges Required (PR) | High |
+| User Interaction (UI) | None |
+| Scope (S) | Unchanged |
+| Confidentiality (C) | Low |
+| Integrity (I) | None |
+| Availability (A) | None |
+
+### CVSS v2.0 Vector
+| Metric | Value |
+| ------------- |-------------|
+| Access Vector (AV) | Local |
+| Access Complexity (AC) | Low |
+| Authentication (Au) | None |
+| Confidentiality Impact (C) | Partial |
+| Integrity Impact (I) | None |
+| Availability Impact (A) | None |
+
 ### Affected Software {.with_icon .affected_software}
 | Name | Vendor           | Start Version | End Version |
 | ------------- |-------------|-----|----|
@@ -257,10 +348,9 @@ It was discovered that the Subiquity installer for Ubuntu Server logged the LUKS
 				By:        "baz source",
 				Date:      "2020-01-08 12:19:15 +0000",
 				Vulnerability: Vulnerability{
-					ID:    "CVE-2020-1234",
-					CWEID: "CWE-269",
-					CWEInfo: WeaknessType{
-						Name: "foo cwe info name",
+					ID: "CVE-2020-1234",
+					Weaknesses: []Weakness{
+						{ID: "CWE-269", Info: WeaknessType{ID: 269, Name: "foo cwe info name"}},
 					},
 					Description: "foo Description",
 					References: []string{
@@ -313,13 +403,20 @@ header_subtitle: "foo cwe info name"
 sidebar_additional_info_nvd: "https://nvd.nist.gov/vuln/detail/CVE-2020-1234"
 sidebar_additional_info_cwe: "https://cwe.mitre.org/data/definitions/269.html"
 
+cvss_nvd_v4_vector: "N/A"
+cvss_nvd_v4_score: "0"
+cvss_nvd_v4_severity: "N/A"
+cvss_nvd_v4_source: "N/A"
+
 cvss_nvd_v3_vector: "CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H"
 cvss_nvd_v3_score: "4.5"
 cvss_nvd_v3_severity: "LOW"
+cvss_nvd_v3_source: "N/A"
 
 cvss_nvd_v2_vector: "AV:L/AC:L/Au:N/C:C/I:C/A:C"
 cvss_nvd_v2_score: "3.4"
 cvss_nvd_v2_severity: "HIGH"
+cvss_nvd_v2_source: "N/A"
 
 redhat_v2_vector: "N/A"
 redhat_v2_score: "0"
@@ -333,9 +430,36 @@ ubuntu_vector: "N/A"
 ubuntu_score: "N/A"
 ubuntu_severity: "N/A"
 
+debian_severity: "N/A"
+
 ---
 
 foo Description
+### Weakness {.with_icon .weakness}
+[CWE-269: foo cwe info name](/cwe/cwe-269)
+
+### CVSS v3.1 Vector
+| Metric | Value |
+| ------------- |-------------|
+| Attack Vector (AV) | Local |
+| Attack Complexity (AC) | Low |
+| Privileges Required (PR) | Low |
+| User Interaction (UI) | None |
+| Scope (S) | Unchanged |
+| Confidentiality (C) | High |
+| Integrity (I) | High |
+| Availability (A) | High |
+
+### CVSS v2.0 Vector
+| Metric | Value |
+| ------------- |-------------|
+| Access Vector (AV) | Local |
+| Access Complexity (AC) | Low |
+| Authentication (Au) | None |
+| Confidentiality Impact (C) | Complete |
+| Integrity Impact (I) | Complete |
+| Availability Impact (A) | Complete |
+
 ### Affected Software {.with_icon .affected_software}
 | Name | Vendor           | Start Version | End Version |
 | ------------- |-------------|-----|----|
@@ -368,6 +492,241 @@ foo Description
 
 }
 
+func TestParseVulnerabilityConfigurations(t *testing.T) {
+	bp, err := parseVulnerabilityJSONFile("../goldens/json/nvd-configurations/CVE-2023-1389.json")
+	require.NoError(t, err)
+
+	vuln := bp.Vulnerability
+	require.Len(t, vuln.Configurations, 3)
+	assert.Equal(t, Configuration{
+		Operator: "AND",
+		Nodes: []ConfigurationNode{
+			{Operator: "OR", Matches: []CPEMatch{{
+				Vulnerable:   true,
+				Criteria:     "cpe:2.3:o:tp-link:archer_ax21_firmware:*:*:*:*:*:*:*:*",
+				Vendor:       "tp-link",
+				Name:         "archer_ax21_firmware",
+				EndExcluding: "1.1.4_build_20230219",
+			}}},
+			{Operator: "OR", Matches: []CPEMatch{{
+				Criteria: "cpe:2.3:h:tp-link:archer_ax21:3.0:*:*:*:*:*:*:*",
+				Vendor:   "tp-link",
+				Name:     "archer_ax21",
+				Version:  "3.0",
+			}}},
+		},
+	}, vuln.Configurations[0])
+	assert.True(t, vuln.Configurations[2].Nodes[1].Negate)
+	assert.True(t, vuln.HasConditions())
+
+	// the hardware the firmware runs on and the excluded OS aren't affected software
+	var names []string
+	for _, as := range vuln.AffectedSoftware {
+		names = append(names, as.Name)
+	}
+	assert.Equal(t, []string{"archer_ax21_firmware", "archer_ax1800_firmware", "archer_ax1800_firmware", "tether"}, names)
+
+	var b strings.Builder
+	require.NoError(t, VulnerabilityPostToMarkdown(bp, &b, ""))
+	assert.Contains(t, b.String(), `### Applicability {.with_icon .affected_software}
+
+**Configuration 1**, all of the following:
+- Any of
+  - Tp-link Archer_ax21_firmware, up to 1.1.4_build_20230219 (excluding)
+- Running on or with any of
+  - Tp-link Archer_ax21, version 3.0
+
+**Configuration 2**:
+- Any of
+  - Tp-link Archer_ax1800_firmware, version 1.1.2
+  - Tp-link Archer_ax1800_firmware, from 1.0.0 (including) up to 1.1.3 (including)
+
+**Configuration 3**, all of the following:
+- Any of
+  - Tp-link Tether, up to 4.4.0 (excluding)
+- Running on or with none of
+  - Apple Iphone_os, all versions
+`)
+
+	simple, err := parseVulnerabilityJSONFile("../goldens/json/nvd/CVE-2020-0001.json")
+	require.NoError(t, err)
+	assert.False(t, simple.Vulnerability.HasConditions())
+}
+
+func TestParseVulnerabilityCVSSV4(t *testing.T) {
+	bp, err := parseVulnerabilityJSONFile("../goldens/json/nvd-cvss/CVE-2024-3400.json")
+	require.NoError(t, err)
+
+	vuln := bp.Vulnerability
+	assert.Equal(t, "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H", vuln.CVSS.V4Vector)
+	assert.Equal(t, 10.0, vuln.CVSS.V4Score)
+	assert.Equal(t, "CRITICAL", vuln.NVDSeverityV4)
+	assert.Equal(t, "psirt@paloaltonetworks.com", vuln.CVSS.V4Source)
+	assert.Equal(t, "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", vuln.CVSS.V3Vector)
+
+	var b strings.Builder
+	require.NoError(t, VulnerabilityPostToMarkdown(bp, &b, ""))
+	assert.Contains(t, b.String(), `cvss_nvd_v4_vector: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H"
+cvss_nvd_v4_score: "10"
+cvss_nvd_v4_severity: "CRITICAL"
+cvss_nvd_v4_source: "psirt@paloaltonetworks.com"
+`)
+	assert.Contains(t, b.String(), `### CVSS Scores
+| Source | Type | Version | Vector | Score | Severity |
+| ------------- |-------------|-----|----|----|----|
+| psirt@paloaltonetworks.com | Secondary | 4.0 | CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H | 10 | CRITICAL |
+| nvd@nist.gov | Primary | 3.1 | CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H | 10 | CRITICAL |
+| psirt@paloaltonetworks.com | Secondary | 3.1 | CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H | 10 | CRITICAL |
+`)
+	assert.Contains(t, b.String(), `### CVSS v4.0 Vector
+| Metric | Value |
+| ------------- |-------------|
+| Attack Vector (AV) | Network |
+| Attack Complexity (AC) | Low |
+| Attack Requirements (AT) | None |
+| Privileges Required (PR) | None |
+| User Interaction (UI) | None |
+| Vulnerable System Confidentiality (VC) | High |
+| Vulnerable System Integrity (VI) | High |
+| Vulnerable System Availability (VA) | High |
+| Subsequent System Confidentiality (SC) | High |
+| Subsequent System Integrity (SI) | High |
+| Subsequent System Availability (SA) | High |
+`)
+	assert.Empty(t, vuln.checkScores())
+}
+
+func TestSelectScores(t *testing.T) {
+	bp, err := parseVulnerabilityJSONFile("../goldens/json/nvd-cvss/CVE-2024-3400.json")
+	require.NoError(t, err)
+	vuln := bp.Vulnerability
+	require.Len(t, vuln.Scores, 3)
+	assert.Equal(t, CVSSScore{
+		Source:   "psirt@paloaltonetworks.com",
+		Type:     "Secondary",
+		Version:  "4.0",
+		Vector:   "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H",
+		Score:    10,
+		Severity: "CRITICAL",
+	}, vuln.Scores[0])
+
+	vuln.Scores = []CVSSScore{
+		{Source: "nvd@nist.gov", Type: "Primary", Version: "3.0", Vector: "CVSS:3.0/nvd", Score: 7.5, Severity: "HIGH"},
+		{Source: "secalert@redhat.com", Type: "Secondary", Version: "3.1", Vector: "CVSS:3.1/redhat", Score: 5.3, Severity: "MEDIUM"},
+		{Source: "nvd@nist.gov", Type: "Primary", Version: "3.1", Vector: "CVSS:3.1/nvd", Score: 7.8, Severity: "HIGH"},
+		{Source: "secalert@redhat.com", Type: "Secondary", Version: "2.0", Vector: "AV:N/redhat", Score: 5, Severity: "MEDIUM"},
+	}
+
+	vuln.selectScores(defaultScorePrecedence)
+	assert.Equal(t, CVSS{V2Vector: "AV:N/redhat", V2Score: 5, V2Source: "secalert@redhat.com", V3Vector: "CVSS:3.1/nvd", V3Score: 7.8, V3Source: "nvd@nist.gov"}, vuln.CVSS)
+	assert.Equal(t, "MEDIUM", vuln.NVDSeverityV2)
+
+	vuln.selectScores([]string{"secalert@redhat.com"})
+	assert.Equal(t, CVSS{V2Vector: "AV:N/redhat", V2Score: 5, V2Source: "secalert@redhat.com", V3Vector: "CVSS:3.1/redhat", V3Score: 5.3, V3Source: "secalert@redhat.com"}, vuln.CVSS)
+	assert.Equal(t, "MEDIUM", vuln.NVDSeverityV3)
+
+	vuln.selectScores([]string{"Primary"})
+	assert.Equal(t, "CVSS:3.1/nvd", vuln.CVSS.V3Vector)
+}
+
+func TestVectorBreakdowns(t *testing.T) {
+	vuln := Vulnerability{
+		CVSS: CVSS{V3Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", V3Score: 9.8, V2Vector: "AV:N/AC:L/Au"},
+		Vendors: []VendorInfo{{
+			Name: "redhat",
+			CVSS: CVSS{V3Vector: "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", V3Score: 5.9, V2Vector: "N/A"},
+		}},
+	}
+
+	var titles []string
+	for _, b := range vuln.VectorBreakdowns() {
+		titles = append(titles, b.Title)
+	}
+	assert.Equal(t, []string{"CVSS v3.1 Vector", "Red Hat CVSS v3.1 Vector"}, titles)
+
+	vuln.Scores = []CVSSScore{
+		{Source: "nvd@nist.gov", Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", Score: 9.8},
+		{Source: "cna@example.com", Vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", Score: 7.5},
+		{Source: "nvd@nist.gov", Vector: "AV:N/AC:L/Au", Score: 5},
+	}
+	var errs []string
+	for _, err := range vuln.checkScores() {
+		errs = append(errs, err.Error())
+	}
+	assert.Equal(t, []string{
+		"cna@example.com: published score 7.5 doesn't match 9.8 computed from CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		`nvd@nist.gov: malformed metric "Au" in "AV:N/AC:L/Au"`,
+	}, errs)
+}
+
+func TestWeaknesses(t *testing.T) {
+	nvd := WeaknessSource{Source: "nvd@nist.gov", Type: "Primary"}
+	bp := VulnerabilityPost{Title: "CVE-2020-1234", Vulnerability: Vulnerability{
+		ID: "CVE-2020-1234",
+		Weaknesses: []Weakness{
+			{ID: "NVD-CWE-Other", Sources: []WeaknessSource{nvd}},
+			{ID: "CWE-416", Sources: []WeaknessSource{nvd, {Source: "cna@example.com", Type: "Secondary"}}},
+			{ID: "CWE-787", Sources: []WeaknessSource{nvd}},
+		},
+	}}
+	assert.Equal(t, []string{"CWE-416", "CWE-787"}, []string{bp.Vulnerability.CWEs()[0].ID, bp.Vulnerability.CWEs()[1].ID})
+
+	require.NoError(t, AddCWEInformation(&bp, "../goldens/cwe"))
+	assert.Equal(t, "Generation of Error Message Containing Sensitive Information", bp.ShortName)
+	require.Len(t, bp.Vulnerability.LoadedCWEs(), 1)
+
+	var page bytes.Buffer
+	require.NoError(t, VulnerabilityPostToMarkdown(bp, &page, ""))
+	assert.Contains(t, page.String(), `
+### Weakness {.with_icon .weakness}
+NVD-CWE-Other: Other
+
+NVD is only using a subset of CWE for mapping instead of the entire CWE, and the weakness type is not covered by that subset.
+
+Mapped by nvd@nist.gov (Primary)
+
+### Weakness {.with_icon .weakness}
+[CWE-416: Generation of Error Message Containing Sensitive Information](/cwe/cwe-416)
+
+The software generates an error message that includes sensitive information about its environment, users, or associated data.
+
+Mapped by nvd@nist.gov (Primary), cna@example.com (Secondary)
+
+### Weakness {.with_icon .weakness}
+[CWE-787](https://cwe.mitre.org/data/definitions/787.html)
+
+Mapped by nvd@nist.gov (Primary)
+`)
+	assert.Contains(t, page.String(), "\nsidebar_additional_info_cwe: \"https://cwe.mitre.org/data/definitions/416.html\"\n")
+	// the sections of a single loaded CWE don't name it
+	assert.Contains(t, page.String(), "\n### Potential Mitigations {.with_icon .mitigations}\n")
+
+	// the sections of several loaded CWEs do
+	bp.Vulnerability.Weaknesses[2].Info = WeaknessType{ID: 787, Name: "Out-of-bounds Write", PotentialMitigations: PotentialMitigationsType{
+		Mitigation: []Mitigation{{
+			Phase:       []PhaseEnumeration{"Requirements"},
+			Strategy:    "Language Selection",
+			Description: StructuredTextType{"Use a language that performs its own memory management."},
+		}},
+	}}
+	page.Reset()
+	require.NoError(t, VulnerabilityPostToMarkdown(bp, &page, ""))
+	assert.Contains(t, page.String(), "\n### Potential Mitigations for CWE-416 {.with_icon .mitigations}\n")
+	assert.Contains(t, page.String(), `
+### Potential Mitigations for CWE-787 {.with_icon .mitigations}
+
+#### Requirements
+- **Language Selection**: Use a language that performs its own memory management.
+`)
+
+	// NVD has no CWE to link to
+	bp.Vulnerability.Weaknesses = []Weakness{{ID: "NVD-CWE-noinfo", Sources: []WeaknessSource{nvd}}}
+	page.Reset()
+	require.NoError(t, VulnerabilityPostToMarkdown(bp, &page, ""))
+	assert.NotContains(t, page.String(), "sidebar_additional_info_cwe")
+	assert.Contains(t, page.String(), "\nNVD-CWE-noinfo: Insufficient Information\n")
+}
+
 func TestGetCustomContentFromMarkdown(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -436,7 +795,11 @@ func TestGenerateVulnerabilityPages(t *testing.T) {
 		err := json.Unmarshal(b, &weaknesses)
 		require.NoError(t, err)
 
-		generateVulnerabilityPages(nvdDir, cweDir, postsDir, "2022")
+		capec, err := loadCAPECCatalog("../goldens/capec/capec.xml")
+		require.NoError(t, err)
+
+		g := &vulnGenerator{ctx: newTestContext(nil, postsDir, nil), cweDir: cweDir, postsDir: postsDir, concurrency: 2, vendors: testVendors(), capec: capec}
+		require.NoError(t, g.generatePages([]source.Document{{ID: "2022", Path: nvdDir}}))
 
 		gotFiles, err := getAllFiles(postsDir)
 		require.NoError(t, err)
@@ -460,19 +823,27 @@ avd_page_type: nvd_page
 
 date_published: 2020-01-08 07:15:12 +0000
 date_modified: 2022-01-01 08:01:34 +0000
+vuln_status: "Analyzed"
 
 header_subtitle: "Generation of Error Message Containing Sensitive Information"
 
 sidebar_additional_info_nvd: "https://nvd.nist.gov/vuln/detail/CVE-2020-0002"
 sidebar_additional_info_cwe: "https://cwe.mitre.org/data/definitions/416.html"
 
+cvss_nvd_v4_vector: "N/A"
+cvss_nvd_v4_score: "0"
+cvss_nvd_v4_severity: "N/A"
+cvss_nvd_v4_source: "N/A"
+
 cvss_nvd_v3_vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:U/C:H/I:H/A:H"
 cvss_nvd_v3_score: "8.8"
 cvss_nvd_v3_severity: "HIGH"
+cvss_nvd_v3_source: "nvd@nist.gov"
 
 cvss_nvd_v2_vector: "AV:N/AC:M/Au:N/C:C/I:C/A:C"
 cvss_nvd_v2_score: "9.3"
 cvss_nvd_v2_severity: "HIGH"
+cvss_nvd_v2_source: "nvd@nist.gov"
 
 redhat_v2_vector: "AV:N/AC:M/Au:N/C:P/I:N/A:N"
 redhat_v2_score: "4.3"
@@ -486,29 +857,84 @@ ubuntu_vector: "N/A"
 ubuntu_score: "N/A"
 ubuntu_severity: "LOW"
 
+debian_severity: "N/A"
+
 ---
 
 In ih264d_init_decoder of ih264d_api.c, there is a possible out of bounds write due to a use after free. This could lead to remote code execution with no additional execution privileges needed. User interaction is needed for exploitation Product: Android Versions: Android-8.0, Android-8.1, Android-9, and Android-10 Android ID: A-142602711
 ### Weakness {.with_icon .weakness}
+[CWE-416: Generation of Error Message Containing Sensitive Information](/cwe/cwe-416)
+
 The software generates an error message that includes sensitive information about its environment, users, or associated data.
 
+Mapped by nvd@nist.gov (Primary)
+
+### Weakness {.with_icon .weakness}
+[CWE-787](https://cwe.mitre.org/data/definitions/787.html)
+
+Mapped by nvd@nist.gov (Primary)
+
+### CVSS Scores
+| Source | Type | Version | Vector | Score | Severity |
+| ------------- |-------------|-----|----|----|----|
+| nvd@nist.gov | Primary | 3.1 | CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:U/C:H/I:H/A:H | 8.8 | HIGH |
+| nvd@nist.gov | Primary | 2.0 | AV:N/AC:M/Au:N/C:C/I:C/A:C | 9.3 | HIGH |
+
+### CVSS v3.1 Vector
+| Metric | Value |
+| ------------- |-------------|
+| Attack Vector (AV) | Network |
+| Attack Complexity (AC) | Low |
+| Privileges Required (PR) | None |
+| User Interaction (UI) | Required |
+| Scope (S) | Unchanged |
+| Confidentiality (C) | High |
+| Integrity (I) | High |
+| Availability (A) | High |
+
+### CVSS v2.0 Vector
+| Metric | Value |
+| ------------- |-------------|
+| Access Vector (AV) | Network |
+| Access Complexity (AC) | Medium |
+| Authentication (Au) | None |
+| Confidentiality Impact (C) | Complete |
+| Integrity Impact (I) | Complete |
+| Availability Impact (A) | Complete |
+
+### Red Hat CVSS v2.0 Vector
+| Metric | Value |
+| ------------- |-------------|
+| Access Vector (AV) | Network |
+| Access Complexity (AC) | Medium |
+| Authentication (Au) | None |
+| Confidentiality Impact (C) | Partial |
+| Integrity Impact (I) | None |
+| Availability Impact (A) | None |
+
 ### Affected Software {.with_icon .affected_software}
-| Name | Vendor           | Start Version | End Version |
-| ------------- |-------------|-----|----|
-| Android | Google | 8.0 (including) | 8.0 (including)|
-| Android | Google | 8.1 (including) | 8.1 (including)|
-| Android | Google | 9.0-beta1 (including) | 9.0-beta1 (including)|
-| Android | Google | 10.0 (including) | 10.0 (including)|
-| Red Hat Enterprise Linux 6 Supplementary | RedHat | chromium-browser-80.0.3987.87-1.el6_10 | *|
-| Tar | Ubuntu | bionic | *|
-| Tar | Ubuntu | cosmic | *|
-| Tar | Ubuntu | devel | *|
-| Tar | Ubuntu | disco | *|
-| Tar | Ubuntu | eoan | *|
-| Tar | Ubuntu | focal | *|
-| Tar | Ubuntu | trusty | *|
-| Tar | Ubuntu | upstream | *|
-| Tar | Ubuntu | xenial | *|
+| Name | Vendor           | Start Version | End Version | Status | Notes |
+| ------------- |-------------|-----|----|----|----|
+| Android | Google | 8.0 (including) | 8.0 (including) |  |  |
+| Android | Google | 8.1 (including) | 8.1 (including) |  |  |
+| Android | Google | 9.0-beta1 (including) | 9.0-beta1 (including) |  |  |
+| Android | Google | 10.0 (including) | 10.0 (including) |  |  |
+| Chromium-browser | RedHat/Red Hat Enterprise Linux 6 Supplementary | * | 80.0.3987.87-1.el6_10 (excluding) | fixed | [RHSA-2020:0514](https://access.redhat.com/errata/RHSA-2020:0514) |
+
+### Ubuntu Releases {.with_icon .affected_software}
+| Package | Release | Status | Fixed Version | Priority | Notes |
+| ------------- |-------------|-----|----|----|----|
+| tar | bionic | {{< status "needed" >}} |  | low |  |
+| tar | cosmic | {{< status "ignored" >}} |  | low | reached end-of-life |
+| tar | devel | {{< status "needed" >}} |  | low |  |
+| tar | disco | {{< status "ignored" >}} |  | low | reached end-of-life |
+| tar | eoan | {{< status "ignored" >}} |  | low | reached end-of-life |
+| tar | focal | {{< status "needed" >}} |  | low |  |
+| tar | precise/esm | {{< status "needs-triage" >}} |  | low |  |
+| tar | trusty | {{< status "pending" >}} |  | low | pending resolution |
+| tar | trusty/esm | {{< status "needs-triage" >}} |  | low |  |
+| tar | upstream | {{< status "released" >}} | 1.32 | low |  |
+| tar | xenial | {{< status "needed" >}} |  | low |  |
 
 ### Extended Description
 The sensitive information may be valuable information on its own (such as a password), or it may be useful for launching other, more serious attacks. The error message may be created in different ways:
@@ -517,16 +943,38 @@ The sensitive information may be valuable information on its own (such as a pass
                 
 An attacker may use the contents of error messages to help launch another, more focused attack. For example, an attempt to exploit a path traversal weakness (CWE-22) might yield the full pathname of the installed application. In turn, this could be used to select the proper number of ".." sequences to navigate to the targeted file. An attack using SQL injection (CWE-89) might not initially succeed, but an error message could reveal the malformed query, which would expose query logic and possibly even passwords or other sensitive information used within the query.
 
+### Common Consequences
+| Impact | Confidentiality |
+| ------------- |-------------|
+| Read Application Data | ✓ |
+
 ### Potential Mitigations {.with_icon .mitigations}
+
+#### Implementation
 - Ensure that error messages only contain minimal details that are useful to the intended audience, and nobody else. The messages need to strike the balance between being too cryptic and not being cryptic enough. They should not necessarily reveal the methods that were used to determine the error. Such detailed information can be used to refine the original attack to increase the chances of success.
-- If errors must be tracked in some detail, capture them in log messages - but consider what could occur if the log messages can be viewed by attackers. Avoid recording highly sensitive information such as passwords in any form. Avoid inconsistent messaging that might accidentally tip off an attacker about internal state, such as whether a username is valid or not.
+
+  If errors must be tracked in some detail, capture them in log messages - but consider what could occur if the log messages can be viewed by attackers. Avoid recording highly sensitive information such as passwords in any form. Avoid inconsistent messaging that might accidentally tip off an attacker about internal state, such as whether a username is valid or not.
+- **Attack Surface Reduction**
+- **Compilation or Build Hardening**
+- **Environment Hardening**
+
+#### Build and Compilation
+- **Compilation or Build Hardening**
+- **Environment Hardening**
 
 ### Related Attack Patterns {.with_icon .related_patterns}
-- https://cwe.mitre.org/data/definitions/214.html
-- https://cwe.mitre.org/data/definitions/215.html
-- https://cwe.mitre.org/data/definitions/463.html
-- https://cwe.mitre.org/data/definitions/54.html
-- https://cwe.mitre.org/data/definitions/7.html
+- [CAPEC-214](https://capec.mitre.org/data/definitions/214.html)
+- [CAPEC-215](https://capec.mitre.org/data/definitions/215.html)
+- [CAPEC-463: Padding Oracle Crypto Attack](/capec/capec-463)
+  - Severity: High
+- [CAPEC-54: Query System for Information](/capec/capec-54)
+  - Likelihood: High
+  - Severity: Medium
+  - Prerequisites: This class of attacks requires that the adversary be able to interact with the application.
+- [CAPEC-7: Blind SQL Injection](/capec/capec-7)
+  - Likelihood: High
+  - Severity: High
+  - Prerequisites: SQL queries used by the application to store, retrieve or modify data. User-controllable input that is not properly validated by the application as part of SQL queries.
 
 
 ### References  {.with_icon .references}
@@ -552,7 +1000,8 @@ An attacker may use the contents of error messages to help launch another, more
 		b1, _ := ioutil.ReadFile("../goldens/markdown/CVE-2020-0002.md")
 		_ = ioutil.WriteFile(filepath.Join(postsDir, "CVE-2020-0002.md"), b1, 0600)
 
-		generateVulnerabilityPages(nvdDir, cweDir, postsDir, "2022")
+		g := &vulnGenerator{ctx: newTestContext(nil, postsDir, nil), cweDir: cweDir, postsDir: postsDir, concurrency: 2, vendors: testVendors()}
+		require.NoError(t, g.generatePages([]source.Document{{ID: "2022", Path: nvdDir}}))
 
 		gotFiles, err := getAllFiles(postsDir)
 		require.NoError(t, err)
@@ -576,13 +1025,20 @@ header_subtitle: "Generation of Error Message Containing Sensitive Information"
 sidebar_additional_info_nvd: "https://nvd.nist.gov/vuln/detail/CVE-2020-0002"
 sidebar_additional_info_cwe: "https://cwe.mitre.org/data/definitions/416.html"
 
+cvss_nvd_v4_vector: "N/A"
+cvss_nvd_v4_score: "0"
+cvss_nvd_v4_severity: "N/A"
+cvss_nvd_v4_source: "N/A"
+
 cvss_nvd_v3_vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:U/C:H/I:H/A:H"
 cvss_nvd_v3_score: "8.8"
 cvss_nvd_v3_severity: "HIGH"
+cvss_nvd_v3_source: "nvd@nist.gov"
 
 cvss_nvd_v2_vector: "AV:N/AC:M/Au:N/C:C/I:C/A:C"
 cvss_nvd_v2_score: "9.3"
 cvss_nvd_v2_severity: "HIGH"
+cvss_nvd_v2_source: "nvd@nist.gov"
 
 redhat_v2_vector: "AV:N/AC:M/Au:N/C:P/I:N/A:N"
 redhat_v2_score: "4.3"
@@ -596,6 +1052,8 @@ ubuntu_vector: "N/A"
 ubuntu_score: "N/A"
 ubuntu_severity: "LOW"
 
+debian_severity: "N/A"
+
 ---
 
 In ih264d_init_decoder of ih264d_api.c, there is a possible out of bounds write due to a use after free. This could lead to remote code execution with no additional execution privileges needed. User interaction is needed for exploitation Product: Android Versions: Android-8.0, Android-8.1, Android-9, and Android-10 Android ID: A-142602711
@@ -603,22 +1061,28 @@ In ih264d_init_decoder of ih264d_api.c, there is a possible out of bounds write
 The software generates an error message that includes sensitive information about its environment, users, or associated data.
 
 ### Affected Software {.with_icon .affected_software}
-| Name | Vendor           | Start Version | End Version |
-| ------------- |-------------|-----|----|
-| Android | Google | 1.1.1 | 1.1.1c|
-| Android | Google | 8.1 | 8.1|
-| Android | Google | 9.0 | 9.0|
-| Android | Google | 10.0 | 10.0|
-| Red Hat Enterprise Linux 6 Supplementary | RedHat | chromium-browser-80.0.3987.87-1.el6_10 | *|
-| Tar | Ubuntu | bionic | *|
-| Tar | Ubuntu | cosmic | *|
-| Tar | Ubuntu | devel | *|
-| Tar | Ubuntu | disco | *|
-| Tar | Ubuntu | eoan | *|
-| Tar | Ubuntu | focal | *|
-| Tar | Ubuntu | trusty | *|
-| Tar | Ubuntu | upstream | *|
-| Tar | Ubuntu | xenial | *|
+| Name | Vendor           | Start Version | End Version | Status | Notes |
+| ------------- |-------------|-----|----|----|----|
+| Android | Google | 1.1.1 | 1.1.1c |  |  |
+| Android | Google | 8.1 | 8.1 |  |  |
+| Android | Google | 9.0 | 9.0 |  |  |
+| Android | Google | 10.0 | 10.0 |  |  |
+| Chromium-browser | RedHat/Red Hat Enterprise Linux 6 Supplementary | * | 80.0.3987.87-1.el6_10 (excluding) | fixed | [RHSA-2020:0514](https://access.redhat.com/errata/RHSA-2020:0514) |
+
+### Ubuntu Releases {.with_icon .affected_software}
+| Package | Release | Status | Fixed Version | Priority | Notes |
+| ------------- |-------------|-----|----|----|----|
+| tar | bionic | {{< status "needed" >}} |  | low |  |
+| tar | cosmic | {{< status "ignored" >}} |  | low | reached end-of-life |
+| tar | devel | {{< status "needed" >}} |  | low |  |
+| tar | disco | {{< status "ignored" >}} |  | low | reached end-of-life |
+| tar | eoan | {{< status "ignored" >}} |  | low | reached end-of-life |
+| tar | focal | {{< status "needed" >}} |  | low |  |
+| tar | precise/esm | {{< status "needs-triage" >}} |  | low |  |
+| tar | trusty | {{< status "pending" >}} |  | low | pending resolution |
+| tar | trusty/esm | {{< status "needs-triage" >}} |  | low |  |
+| tar | upstream | {{< status "released" >}} | 1.32 | low |  |
+| tar | xenial | {{< status "needed" >}} |  | low |  |
 
 ### Extended Description
 The sensitive information may be valuable information on its own (such as a password), or it may be useful for launching other, more serious attacks. The error message may be created in different ways:
@@ -627,16 +1091,31 @@ The sensitive information may be valuable information on its own (such as a pass
                 
 An attacker may use the contents of error messages to help launch another, more focused attack. For example, an attempt to exploit a path traversal weakness (CWE-22) might yield the full pathname of the installed application. In turn, this could be used to select the proper number of ".." sequences to navigate to the targeted file. An attack using SQL injection (CWE-89) might not initially succeed, but an error message could reveal the malformed query, which would expose query logic and possibly even passwords or other sensitive information used within the query.
 
+### Common Consequences
+| Impact | Confidentiality |
+| ------------- |-------------|
+| Read Application Data | ✓ |
+
 ### Potential Mitigations {.with_icon .mitigations}
+
+#### Implementation
 - Ensure that error messages only contain minimal details that are useful to the intended audience, and nobody else. The messages need to strike the balance between being too cryptic and not being cryptic enough. They should not necessarily reveal the methods that were used to determine the error. Such detailed information can be used to refine the original attack to increase the chances of success.
-- If errors must be tracked in some detail, capture them in log messages - but consider what could occur if the log messages can be viewed by attackers. Avoid recording highly sensitive information such as passwords in any form. Avoid inconsistent messaging that might accidentally tip off an attacker about internal state, such as whether a username is valid or not.
+
+  If errors must be tracked in some detail, capture them in log messages - but consider what could occur if the log messages can be viewed by attackers. Avoid recording highly sensitive information such as passwords in any form. Avoid inconsistent messaging that might accidentally tip off an attacker about internal state, such as whether a username is valid or not.
+- **Attack Surface Reduction**
+- **Compilation or Build Hardening**
+- **Environment Hardening**
+
+#### Build and Compilation
+- **Compilation or Build Hardening**
+- **Environment Hardening**
 
 ### Related Attack Patterns {.with_icon .related_patterns}
-- https://cwe.mitre.org/data/definitions/214.html
-- https://cwe.mitre.org/data/definitions/215.html
-- https://cwe.mitre.org/data/definitions/463.html
-- https://cwe.mitre.org/data/definitions/54.html
-- https://cwe.mitre.org/data/definitions/7.html
+- [CAPEC-214](https://capec.mitre.org/data/definitions/214.html)
+- [CAPEC-215](https://capec.mitre.org/data/definitions/215.html)
+- [CAPEC-463](https://capec.mitre.org/data/definitions/463.html)
+- [CAPEC-54](https://capec.mitre.org/data/definitions/54.html)
+- [CAPEC-7](https://capec.mitre.org/data/definitions/7.html)
 
 
 ### References  {.with_icon .references}
@@ -651,6 +1130,63 @@ bar content`, string(b))
 	})
 }
 
+func TestGenerateVulnerabilityPagesConcurrency(t *testing.T) {
+	docs := []source.Document{
+		{ID: "2020", Path: "../goldens/json/nvd"},
+		{ID: "2022", Path: "../goldens/json/nvd"},
+	}
+
+	render := func(concurrency int) map[string]string {
+		postsDir := t.TempDir()
+		g := &vulnGenerator{ctx: newTestContext(nil, postsDir, nil), cweDir: "../goldens/cwe", postsDir: postsDir, concurrency: concurrency, vendors: testVendors()}
+		require.NoError(t, g.generatePages(docs))
+
+		pages := make(map[string]string)
+		files, err := getAllFiles(postsDir)
+		require.NoError(t, err)
+		for _, file := range files {
+			b, err := os.ReadFile(file)
+			require.NoError(t, err)
+			rel, _ := filepath.Rel(postsDir, file)
+			pages[rel] = string(b)
+		}
+		return pages
+	}
+
+	sequential := render(1)
+	assert.Len(t, sequential, 10) // 4 CVEs and an index per year
+	assert.Equal(t, sequential, render(8))
+}
+
+func TestGenerateVulnerabilityPageIncremental(t *testing.T) {
+	nvdDir := "../goldens/json/nvd"
+	cweDir := "../goldens/cwe"
+	file := filepath.Join(nvdDir, "CVE-2020-0002.json")
+	postsDir := t.TempDir()
+	m := manifest.New(nvdManifestVersion(""))
+	g := &vulnGenerator{ctx: newTestContext(nil, postsDir, nil), manifest: m, cweDir: cweDir, postsDir: postsDir, vendors: testVendors()}
+
+	assert.Equal(t, pageWritten, g.generatePage(file, postsDir))
+	e, ok := m.Get("CVE-2020-0002")
+	require.True(t, ok)
+	// CWE-787 isn't in cweDir yet, adding it renders the page again
+	assert.Equal(t, []string{filepath.Join(cweDir, "CWE-416.json"), filepath.Join(cweDir, "CWE-787.json")}, e.Inputs)
+
+	assert.Equal(t, pageSkipped, g.generatePage(file, postsDir))
+	assert.Equal(t, pageUnchanged, (&vulnGenerator{ctx: g.ctx, cweDir: cweDir, postsDir: postsDir, vendors: g.vendors}).generatePage(file, postsDir))
+
+	// an edited page is rendered again, keeping its custom content so there is nothing to write
+	pageFile := filepath.Join(postsDir, "CVE-2020-0002.md")
+	b, err := os.ReadFile(pageFile)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(pageFile, append(b, "\nSome Aqua content"...), 0600))
+	assert.Equal(t, pageUnchanged, g.generatePage(file, postsDir))
+	b, err = os.ReadFile(pageFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(b), "<!--- Add Aqua content below --->\nSome Aqua content"))
+	assert.Equal(t, pageSkipped, g.generatePage(file, postsDir))
+}
+
 func TestGenerateReservedPages(t *testing.T) {
 	t.Run("no existing info from NVD", func(t *testing.T) {
 		postsDir, _ := ioutil.TempDir("", "TestGenerateReservedPages-postsDir-*")
@@ -659,7 +1195,7 @@ func TestGenerateReservedPages(t *testing.T) {
 		}()
 
 		for _, year := range []string{"2020"} {
-			generateReservedPages(year, fakeClock{}, "../goldens/reserved-no-existing-info", postsDir)
+			generateReservedPages(newTestContext(nil, postsDir, nil), year, fakeClock{}, map[string]bool{"CVE-2020-11932": true}, reservedTestVendors("../goldens/reserved-no-existing-info"), postsDir)
 		}
 
 		// check for one expected file
@@ -694,9 +1230,9 @@ Use subscription-manager directly from the terminal and do not use the --passwor
 
 
 #### Affected Software List
-| Name | Vendor           | Version |
-| ------------- |-------------|-----|
-| Red Hat Enterprise Linux 8 | RedHat | qt5-qtbase-0:5.12.5-6.el8|
+| Name | Vendor           | Start Version | End Version | Status | Notes |
+| ------------- |-------------|-----|----|----|----|
+| Qt5-qtbase | RedHat/Red Hat Enterprise Linux 8 | * | 5.12.5-6.el8 (excluding) | fixed | [RHSA-2020:4690](https://access.redhat.com/errata/RHSA-2020:4690) |
 
 
 ### Ubuntu
@@ -704,14 +1240,35 @@ QPluginLoader in Qt versions 5.0.0 through 5.13.2 would search for certain plugi
 
 
 
-#### Affected Software List
-| Name | Vendor           | Version |
-| ------------- |-------------|-----|
-| Qtbase-opensource-src | Ubuntu/bionic | 5.9.5+dfsg-0ubuntu2.5|
+#### Releases
+| Package | Release | Status | Fixed Version | Priority | Notes |
+| ------------- |-------------|-----|----|----|----|
+| qtbase-opensource-src | bionic | {{< status "released" >}} | 5.9.5+dfsg-0ubuntu2.5 | medium |  |
+| qtbase-opensource-src | devel | {{< status "not-affected" >}} |  | medium | 5.12.5+dfsg-8 |
 
 `, string(got))
 	})
 
+	t.Run("unchanged pages are not written again", func(t *testing.T) {
+		postsDir := t.TempDir()
+		inputDir := "../goldens/reserved-no-existing-info"
+		published := map[string]bool{"CVE-2020-11932": true}
+		generateReservedPages(newTestContext(nil, postsDir, nil), "2020", fakeClock{}, published, reservedTestVendors(inputDir), postsDir)
+		pageFile := filepath.Join(postsDir, "CVE-2020-0569.md")
+		before, err := os.Stat(pageFile)
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+
+		// a later run keeps the date the page was first written with
+		generateReservedPages(newTestContext(nil, postsDir, nil), "2020", realClock{}, published, reservedTestVendors(inputDir), postsDir)
+		after, err := os.Stat(pageFile)
+		require.NoError(t, err)
+		assert.Equal(t, before.ModTime(), after.ModTime())
+		b, err := os.ReadFile(pageFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "\ndate: 2021-04-15T20:55:39Z\n")
+	})
+
 	t.Run("with existing info from NVD", func(t *testing.T) {
 		postsDir, _ := ioutil.TempDir("", "TestGenerateReservedPages-postsDir-*")
 		defer func() {
@@ -719,7 +1276,7 @@ QPluginLoader in Qt versions 5.0.0 through 5.13.2 would search for certain plugi
 		}()
 
 		for _, year := range []string{"2020"} {
-			generateReservedPages(year, fakeClock{}, "../goldens/reserved-with-existing-info", postsDir)
+			generateReservedPages(newTestContext(nil, postsDir, nil), year, fakeClock{}, map[string]bool{"CVE-2020-11932": true, "CVE-2020-0569": true}, reservedTestVendors("../goldens/reserved-with-existing-info"), postsDir)
 		}
 
 		// no new reserved page must be created as NVD already has info
@@ -727,3 +1284,91 @@ QPluginLoader in Qt versions 5.0.0 through 5.13.2 would search for certain plugi
 		require.Contains(t, err.Error(), "no such file or directory")
 	})
 }
+
+func TestRenderSkipsReservedPagesOfPublishedCVEs(t *testing.T) {
+	nvdDir := filepath.Join(t.TempDir(), "api", "2020")
+	require.NoError(t, os.MkdirAll(nvdDir, 0755))
+	for _, id := range []string{"CVE-2020-0001", "CVE-2020-0002"} {
+		b, err := os.ReadFile(filepath.Join("../goldens/json/nvd", id+".json"))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(nvdDir, id+".json"), b, 0600))
+	}
+
+	postsDir := t.TempDir()
+	ctx := newTestContext(map[string]string{"redhat": "../goldens/json/redhat", "ubuntu": "../goldens/json/ubuntu"}, postsDir, nil)
+	ctx.Source.Options["vendors"] = "redhat,ubuntu"
+	require.NoError(t, nvdSource{clock: fakeClock{}}.Render(ctx, []source.Document{{ID: "2020", Path: nvdDir}}))
+
+	// Red Hat and Ubuntu know of CVE-2020-0002, which NVD published
+	_, err := os.Stat(filepath.Join(postsDir, "2020", "CVE-2020-0002.md"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(postsDir, "CVE-2020-0002.md"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRejectedAndDisputedCVEs(t *testing.T) {
+	nvdDir := "../goldens/json/nvd-status"
+	postsDir := t.TempDir()
+	g := &vulnGenerator{ctx: newTestContext(nil, postsDir, nil), cweDir: "../goldens/cwe", postsDir: postsDir, vendors: testVendors()}
+
+	t.Run("disputed", func(t *testing.T) {
+		bp, err := parseVulnerabilityJSONFile(filepath.Join(nvdDir, "CVE-2023-35116.json"))
+		require.NoError(t, err)
+		assert.Equal(t, "Modified", bp.Vulnerability.Status)
+		assert.True(t, bp.Vulnerability.Disputed)
+		assert.False(t, bp.Vulnerability.Rejected())
+
+		require.Equal(t, pageWritten, g.generatePage(filepath.Join(nvdDir, "CVE-2023-35116.json"), postsDir))
+		b, err := os.ReadFile(filepath.Join(postsDir, "CVE-2023-35116.md"))
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `
+avd_page_type: nvd_page
+
+date_published: 2023-06-14 02:15:10 +0000
+date_modified: 2024-02-13 07:15:10 +0000
+vuln_status: "Modified"
+disputed: true
+`)
+		assert.Contains(t, string(b), `
+### Disputed
+The validity of this CVE is disputed`)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		bp, err := parseVulnerabilityJSONFile(filepath.Join(nvdDir, "CVE-2023-35117.json"))
+		require.NoError(t, err)
+		assert.True(t, bp.Vulnerability.Rejected())
+		assert.False(t, bp.Vulnerability.Disputed)
+		assert.Equal(t, "DO NOT USE THIS CANDIDATE NUMBER. ConsultIDs: CVE-2023-35116. Reason: This candidate is a duplicate of CVE-2023-35116. Notes: All CVE users should reference CVE-2023-35116 instead of this candidate.", bp.Vulnerability.Description)
+
+		require.Equal(t, pageWritten, g.generatePage(filepath.Join(nvdDir, "CVE-2023-35117.json"), postsDir))
+		b, err := os.ReadFile(filepath.Join(postsDir, "CVE-2023-35117.md"))
+		require.NoError(t, err)
+		assert.Equal(t, `---
+title: "CVE-2023-35117"
+aliases: [
+	"/nvd/cve-2023-35117"
+]
+
+date: 2023-06-14 02:15:10 +0000
+category: vulnerabilities
+draft: false
+
+avd_page_type: rejected_page
+
+date_published: 2023-06-14 02:15:10 +0000
+date_modified: 2023-07-06 04:15:09 +0000
+
+vuln_status: "Rejected"
+
+sidebar_additional_info_nvd: "https://nvd.nist.gov/vuln/detail/CVE-2023-35117"
+
+---
+
+This CVE has been __REJECTED__ by NVD. The CVE ID is not in use, because it was withdrawn by its CNA, is a duplicate of another CVE or turned out not to be a vulnerability.
+
+DO NOT USE THIS CANDIDATE NUMBER. ConsultIDs: CVE-2023-35116. Reason: This candidate is a duplicate of CVE-2023-35116. Notes: All CVE users should reference CVE-2023-35116 instead of this candidate.
+
+<!--- Add Aqua content below --->`, string(b))
+	})
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
+)
+
+// osvRecord is the part of an OSV record (https://ossf.github.io/osv-schema/)
+// that describes what it affects.
+type osvRecord struct {
+	ID        string   `json:"id"`
+	Aliases   []string `json:"aliases"`
+	Withdrawn string   `json:"withdrawn"`
+	Affected  []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []osvRange `json:"ranges"`
+	} `json:"affected"`
+}
+
+type osvRange struct {
+	Type   string `json:"type"`
+	Repo   string `json:"repo"`
+	Events []struct {
+		Introduced   string `json:"introduced"`
+		Fixed        string `json:"fixed"`
+		LastAffected string `json:"last_affected"`
+	} `json:"events"`
+}
+
+// CVEIDs are the CVEs a record is about, either as its own ID or as aliases.
+func (r osvRecord) CVEIDs() []string {
+	var ids []string
+	for _, id := range append([]string{r.ID}, r.Aliases...) {
+		if strings.HasPrefix(id, "CVE-") && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// AffectedSoftware lists the version ranges of every package the record
+// affects. SEMVER and ECOSYSTEM ranges are in the versions of the package,
+// GIT ranges are in commits of the repository.
+func (r osvRecord) AffectedSoftware() []AffectedSoftware {
+	var affected []AffectedSoftware
+	for _, a := range r.Affected {
+		for _, rng := range a.Ranges {
+			name, vendor := a.Package.Name, a.Package.Ecosystem
+			if rng.Type == "GIT" {
+				name, vendor = strings.TrimPrefix(strings.TrimPrefix(rng.Repo, "https://"), "http://"), "Git"
+			}
+			for _, versions := range rng.versions() {
+				as := AffectedSoftware{Name: name, Vendor: vendor, StartVersion: versions[0], EndVersion: versions[1]}
+				if !slices.Contains(affected, as) {
+					affected = append(affected, as)
+				}
+			}
+		}
+	}
+	return affected
+}
+
+// versions pairs every introduced event with the fixed or last_affected event
+// that closes it, ranges left open end at "*".
+func (r osvRange) versions() [][2]string {
+	version := func(v string) string {
+		if r.Type == "GIT" && len(v) > 12 {
+			return v[:12]
+		}
+		return v
+	}
+
+	var ranges [][2]string
+	start := ""
+	for _, e := range r.Events {
+		switch {
+		case e.Introduced != "":
+			if start != "" {
+				ranges = append(ranges, [2]string{start, "*"})
+			}
+			start = "*"
+			if e.Introduced != "0" {
+				start = version(e.Introduced) + " (including)"
+			}
+		case e.Fixed != "" && start != "":
+			ranges = append(ranges, [2]string{start, version(e.Fixed) + " (excluding)"})
+			start = ""
+		case e.LastAffected != "" && start != "":
+			ranges = append(ranges, [2]string{start, version(e.LastAffected) + " (including)"})
+			start = ""
+		}
+	}
+	if start != "" {
+		ranges = append(ranges, [2]string{start, "*"})
+	}
+	return ranges
+}
+
+// loadOSVRecords reads every OSV record under dir and returns what they affect
+// by the CVEs they are about. Withdrawn records are left out, files that can't
+// be read are recorded and skipped.
+func loadOSVRecords(ctx *source.Context, dir string) (map[string][]AffectedSoftware, error) {
+	files, err := getAllFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byCVE := make(map[string][]AffectedSoftware)
+	for _, file := range files {
+		if filepath.Ext(file) != ".json" {
+			continue
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			ctx.RecordError(file, report.StageRead, err)
+			continue
+		}
+		var r osvRecord
+		if err := json.Unmarshal(b, &r); err != nil {
+			ctx.RecordError(file, report.StageParse, err)
+			continue
+		}
+		if r.ID == "" {
+			ctx.RecordError(file, report.StageParse, fmt.Errorf("no id"))
+			continue
+		}
+		if r.Withdrawn != "" {
+			continue
+		}
+
+		affected := r.AffectedSoftware()
+		for _, id := range r.CVEIDs() {
+			for _, as := range affected {
+				if !slices.Contains(byCVE[id], as) {
+					byCVE[id] = append(byCVE[id], as)
+				}
+			}
+		}
+	}
+	return byCVE, nil
+}
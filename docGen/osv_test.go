@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/avd-generator/report"
+)
+
+func TestLoadOSVRecords(t *testing.T) {
+	ctx := newTestContext(nil, t.TempDir(), nil)
+	ctx.Report = report.New()
+	osv, err := loadOSVRecords(ctx, "../goldens/json/osv")
+	require.NoError(t, err)
+	assert.Zero(t, ctx.Report.Count("test", ""))
+
+	// the withdrawn PYSEC-2021-900 is left out
+	assert.Len(t, osv, 2)
+
+	assert.Equal(t, []AffectedSoftware{
+		{Name: "org.apache.logging.log4j:log4j-core", Vendor: "Maven", StartVersion: "2.13.0 (including)", EndVersion: "2.15.0 (excluding)"},
+		{Name: "org.apache.logging.log4j:log4j-core", Vendor: "Maven", StartVersion: "*", EndVersion: "2.3.1 (excluding)"},
+		{Name: "org.apache.logging.log4j:log4j-core", Vendor: "Maven", StartVersion: "2.4 (including)", EndVersion: "2.12.2 (excluding)"},
+		{Name: "github.com/apache/logging-log4j2", Vendor: "Git", StartVersion: "*", EndVersion: "c77b3cb39312 (excluding)"},
+	}, osv["CVE-2021-44228"])
+
+	assert.Equal(t, []AffectedSoftware{
+		{Name: "example-android-tools", Vendor: "PyPI", StartVersion: "1.0.0 (including)", EndVersion: "1.4.0 (including)"},
+		{Name: "example-android-tools", Vendor: "PyPI", StartVersion: "2.0.0 (including)", EndVersion: "*"},
+	}, osv["CVE-2020-0001"])
+}
+
+func TestLoadOSVRecordsInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "GHSA-xxxx-xxxx-xxxx.json"), []byte(`{"affected": []}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "PYSEC-2024-1.json"), []byte(`{`), 0600))
+
+	ctx := newTestContext(nil, t.TempDir(), nil)
+	ctx.Report = report.New()
+	osv, err := loadOSVRecords(ctx, dir)
+	require.NoError(t, err)
+	assert.Empty(t, osv)
+	assert.Equal(t, 2, ctx.Report.Count("test", report.StageParse))
+}
+
+func TestGenerateVulnerabilityPageOSV(t *testing.T) {
+	ctx := newTestContext(nil, t.TempDir(), nil)
+	osv, err := loadOSVRecords(ctx, "../goldens/json/osv")
+	require.NoError(t, err)
+
+	nvdDir := "../goldens/json/nvd-ghsa"
+	postsDir := t.TempDir()
+	g := &vulnGenerator{ctx: ctx, cweDir: "../goldens/cwe", postsDir: postsDir, osv: osv}
+
+	require.Equal(t, pageWritten, g.generatePage(filepath.Join(nvdDir, "CVE-2021-44228.json"), postsDir))
+	b, err := os.ReadFile(filepath.Join(postsDir, "CVE-2021-44228.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `
+### Affected Software {.with_icon .affected_software}
+| Name | Vendor           | Start Version | End Version |
+| ------------- |-------------|-----|----|
+| Org.apache.logging.log4j:log4j-core | Maven | 2.13.0 (including) | 2.15.0 (excluding)|
+| Org.apache.logging.log4j:log4j-core | Maven | * | 2.3.1 (excluding)|
+| Org.apache.logging.log4j:log4j-core | Maven | 2.4 (including) | 2.12.2 (excluding)|
+| Github.com/apache/logging-log4j2 | Git | * | c77b3cb39312 (excluding)|
+`)
+}
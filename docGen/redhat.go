@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aquasecurity/vuln-list-update/redhat"
+)
+
+// redhatAdapter reads the Red Hat security data API, vuln-list stores a CVE
+// at redhat/<year>/<CVE ID>.json.
+type redhatAdapter struct {
+	dir string
+}
+
+func init() {
+	registerVendorAdapter("redhat", newRedHatAdapter)
+}
+
+func newRedHatAdapter(dir string) VendorAdapter {
+	return redhatAdapter{dir: dir}
+}
+
+func (redhatAdapter) Name() string { return "redhat" }
+
+func (v redhatAdapter) Locate(cveID, year string) ([]string, error) {
+	return locateByYear(v.dir, cveID, year)
+}
+
+func (v redhatAdapter) CVEs(year string) ([]string, error) {
+	return cvesByYear(v.dir, year)
+}
+
+func (v redhatAdapter) Load(cveID, year string) (VendorInfo, error) {
+	b, err := os.ReadFile(filepath.Join(v.dir, year, fmt.Sprintf("%s.json", cveID)))
+	if err != nil {
+		return VendorInfo{}, err
+	}
+	var rh redhat.RedhatCVEJSON
+	if err := json.Unmarshal(b, &rh); err != nil {
+		return VendorInfo{}, err
+	}
+
+	info := VendorInfo{
+		Name:        v.Name(),
+		Description: rh.Bugzilla.Description,
+		Severity:    rh.ThreatSeverity,
+		Mitigation:  rh.Mitigation,
+		CVSS: CVSS{
+			V2Vector: rh.Cvss.CvssScoringVector,
+			V3Vector: rh.Cvss3.Cvss3ScoringVector,
+		},
+	}
+	info.CVSS.V2Score, _ = strconv.ParseFloat(rh.Cvss.CvssBaseScore, 64)
+	info.CVSS.V3Score, _ = strconv.ParseFloat(rh.Cvss3.Cvss3BaseScore, 64)
+
+	for _, release := range rh.AffectedRelease {
+		advisory := redhatAdvisoryLink(release.Advisory)
+		// a product fixed as a whole, such as a container image, names no package
+		if release.Package == "" {
+			info.AffectedSoftware = append(info.AffectedSoftware, AffectedSoftware{
+				Name:         release.ProductName,
+				Vendor:       "RedHat",
+				StartVersion: "*",
+				EndVersion:   "*",
+				Status:       "fixed",
+				Notes:        advisory,
+			})
+			continue
+		}
+		name, version := splitNVR(release.Package)
+		info.AffectedSoftware = append(info.AffectedSoftware, fixedIn("RedHat", release.ProductName, name, strings.TrimPrefix(version, "0:"), advisory))
+	}
+	for _, state := range rh.PackageState {
+		// products that never were vulnerable are left out
+		if strings.EqualFold(state.FixState, "Not affected") {
+			continue
+		}
+		info.AffectedSoftware = append(info.AffectedSoftware, AffectedSoftware{
+			Name:         state.PackageName,
+			Vendor:       fmt.Sprintf("RedHat/%s", state.ProductName),
+			StartVersion: "*",
+			EndVersion:   "*",
+			Status:       strings.ToLower(state.FixState),
+		})
+	}
+	return info, nil
+}
+
+// redhatAdvisoryLink links an advisory, such as RHSA-2020:4690, to its errata page.
+func redhatAdvisoryLink(advisory string) string {
+	if advisory == "" {
+		return ""
+	}
+	return fmt.Sprintf("[%s](https://access.redhat.com/errata/%s)", advisory, advisory)
+}
+
+// locateByYear returns the file of a CVE for vendors that store a file per
+// CVE at <year>/<CVE ID>.json, none when there is no such file.
+func locateByYear(dir, cveID, year string) ([]string, error) {
+	file := filepath.Join(dir, year, fmt.Sprintf("%s.json", cveID))
+	if _, err := os.Stat(file); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return []string{file}, nil
+}
+
+// cvesByYear returns the CVEs of a year of vendors that store a file per CVE
+// at <year>/<CVE ID>.json.
+func cvesByYear(dir, year string) ([]string, error) {
+	files, err := getAllFiles(filepath.Join(dir, year))
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, file := range files {
+		if filepath.Ext(file) == ".json" {
+			ids = append(ids, strings.TrimSuffix(filepath.Base(file), ".json"))
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,132 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aquasecurity/avd-generator/config"
+)
+
+// Stages a failure can be recorded against.
+const (
+	StageLoad   = "load"
+	StageRead   = "read"
+	StageParse  = "parse"
+	StageEnrich = "enrich"
+	// StageValidate is a page that was written from data that doesn't add up,
+	// such as a CVSS score that doesn't match its vector.
+	StageValidate = "validate"
+	StageWrite    = "write"
+	StageRender   = "render"
+)
+
+// Entry is a single failure, usually of one input file.
+type Entry struct {
+	Source string `json:"source"`
+	File   string `json:"file,omitempty"`
+	Stage  string `json:"stage"`
+	Error  string `json:"error"`
+}
+
+// SourceSummary is the outcome of running one source.
+type SourceSummary struct {
+	Name      string  `json:"name"`
+	Documents int     `json:"documents"`
+	Seconds   float64 `json:"seconds"`
+	Errors    int     `json:"errors"`
+	Failed    bool    `json:"failed"`
+}
+
+// Report collects the failures of a generator run. It is safe for concurrent use.
+type Report struct {
+	mu      sync.Mutex
+	Started time.Time       `json:"started"`
+	Sources []SourceSummary `json:"sources"`
+	Errors  []Entry         `json:"errors"`
+}
+
+func New() *Report {
+	return &Report{Started: time.Now().UTC()}
+}
+
+func (r *Report) Add(source, file, stage string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Errors = append(r.Errors, Entry{
+		Source: source,
+		File:   file,
+		Stage:  stage,
+		Error:  err.Error(),
+	})
+}
+
+// Finish records the outcome of a source once it has run.
+func (r *Report) Finish(source string, documents int, duration time.Duration, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Sources = append(r.Sources, SourceSummary{
+		Name:      source,
+		Documents: documents,
+		Seconds:   duration.Seconds(),
+		Errors:    r.count(source, ""),
+		Failed:    failed,
+	})
+}
+
+// Count returns the number of failures of a source in a stage, or in every stage if stage is empty.
+func (r *Report) Count(source, stage string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count(source, stage)
+}
+
+func (r *Report) count(source, stage string) int {
+	var n int
+	for _, e := range r.Errors {
+		if e.Source == source && (stage == "" || e.Stage == stage) {
+			n++
+		}
+	}
+	return n
+}
+
+// Check returns an error describing every threshold the run exceeded.
+func (r *Report) Check(thresholds []config.Threshold) error {
+	var exceeded []string
+	for _, t := range thresholds {
+		if n := r.Count(t.Source, t.Stage); n > t.Max {
+			stage := t.Stage
+			if stage == "" {
+				stage = "any"
+			}
+			exceeded = append(exceeded, fmt.Sprintf("%s had %d %s failures (max %d)", t.Source, n, stage, t.Max))
+		}
+	}
+	if len(exceeded) > 0 {
+		sort.Strings(exceeded)
+		return fmt.Errorf("error thresholds exceeded: %v", exceeded)
+	}
+	return nil
+}
+
+func (r *Report) Write(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sort.SliceStable(r.Errors, func(i, j int) bool {
+		if r.Errors[i].Source != r.Errors[j].Source {
+			return r.Errors[i].Source < r.Errors[j].Source
+		}
+		return r.Errors[i].File < r.Errors[j].File
+	})
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
@@ -0,0 +1,56 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aquasecurity/avd-generator/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	r := New()
+	for i := 0; i < 3; i++ {
+		r.Add("nvd", "CVE-2022-0001.json", StageParse, errors.New("unexpected EOF"))
+	}
+	r.Add("nvd", "CVE-2022-0002.json", StageEnrich, errors.New("bad cwe"))
+
+	assert.Equal(t, 3, r.Count("nvd", StageParse))
+	assert.Equal(t, 4, r.Count("nvd", ""))
+	assert.Equal(t, 0, r.Count("tracee", ""))
+
+	assert.NoError(t, r.Check([]config.Threshold{{Source: "nvd", Stage: StageParse, Max: 3}}))
+	assert.EqualError(t, r.Check([]config.Threshold{
+		{Source: "nvd", Stage: StageParse, Max: 2},
+		{Source: "nvd", Max: 3},
+		{Source: "tracee", Max: 0},
+	}), "error thresholds exceeded: [nvd had 3 parse failures (max 2) nvd had 4 any failures (max 3)]")
+}
+
+func TestWrite(t *testing.T) {
+	r := New()
+	r.Add("nvd", "b.json", StageParse, errors.New("unexpected EOF"))
+	r.Add("defsec", "", StageLoad, errors.New("no such file"))
+	r.Add("nvd", "a.json", StageWrite, errors.New("disk full"))
+	r.Finish("nvd", 10, 2*time.Second, false)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, r.Write(path))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var got Report
+	require.NoError(t, json.Unmarshal(b, &got))
+
+	assert.Equal(t, []Entry{
+		{Source: "defsec", Stage: StageLoad, Error: "no such file"},
+		{Source: "nvd", File: "a.json", Stage: StageWrite, Error: "disk full"},
+		{Source: "nvd", File: "b.json", Stage: StageParse, Error: "unexpected EOF"},
+	}, got.Errors)
+	assert.Equal(t, []SourceSummary{{Name: "nvd", Documents: 10, Seconds: 2, Errors: 2}}, got.Sources)
+}
@@ -0,0 +1,127 @@
+package source
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/aquasecurity/avd-generator/config"
+	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/report"
+)
+
+// Document is a single item read by a source, such as a check definition or a
+// CVE record, ready to be rendered as a page.
+type Document struct {
+	ID   string
+	Path string
+	Data interface{}
+}
+
+// Context is what a source is given to build its pages.
+type Context struct {
+	Config config.Config
+	Source config.Source
+	// Menu is the menu named by the source config, nil when it doesn't contribute to one.
+	Menu *menu.Menu
+	// Report collects per-file failures, they are only logged when it is nil.
+	Report *report.Report
+}
+
+// RecordError logs a failure to process a single file and adds it to the run report.
+func (c *Context) RecordError(file, stage string, err error) {
+	log.Printf("%s: %s %s: %s", c.Source.Name, stage, file, err)
+	if c.Report != nil {
+		c.Report.Add(c.Source.Name, file, stage, err)
+	}
+}
+
+// Input returns the resolved path of one of the source's inputs.
+func (c *Context) Input(name string) string {
+	return c.Config.Input(c.Source, name)
+}
+
+// Output returns the directory the source writes its pages to.
+func (c *Context) Output() string {
+	return c.Config.Output(c.Source)
+}
+
+// Source reads advisories or checks from a checkout and renders them as pages.
+type Source interface {
+	// Load reads the source's inputs into documents.
+	Load(ctx *Context) ([]Document, error)
+	// Render writes a page for each document and adds the source's nodes to ctx.Menu.
+	Render(ctx *Context, docs []Document) error
+}
+
+// FlagSetter is implemented by sources taking command line flags on top of the
+// repo and content flags every source gets.
+type FlagSetter interface {
+	SetFlags(fs *flag.FlagSet, src *config.Source)
+}
+
+type Factory func() Source
+
+var registry = make(map[string]Factory)
+
+// Register makes a source type available to the config. It is meant to be
+// called from the init function of the package implementing the source.
+func Register(typ string, factory Factory) {
+	if _, ok := registry[typ]; ok {
+		panic(fmt.Sprintf("source type %q is registered twice", typ))
+	}
+	registry[typ] = factory
+}
+
+func New(typ string) (Source, error) {
+	factory, ok := registry[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown source type %q", typ)
+	}
+	return factory(), nil
+}
+
+func Types() []string {
+	var types []string
+	for typ := range registry {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Result summarises a single run of a source.
+type Result struct {
+	Name      string
+	Documents int
+	Duration  time.Duration
+	Err       error
+}
+
+// Run loads and renders the pages of a source.
+func Run(ctx *Context, s Source) Result {
+	start := time.Now()
+	result := Result{Name: ctx.Source.Name}
+
+	stage := report.StageLoad
+	docs, err := s.Load(ctx)
+	if err == nil {
+		stage = report.StageRender
+		err = s.Render(ctx, docs)
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("%s: %w", stage, err)
+	}
+
+	result.Documents = len(docs)
+	result.Duration = time.Since(start)
+	if ctx.Report != nil {
+		if err != nil {
+			ctx.Report.Add(result.Name, "", stage, err)
+		}
+		ctx.Report.Finish(result.Name, result.Documents, result.Duration, err != nil)
+	}
+	return result
+}
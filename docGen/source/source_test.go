@@ -0,0 +1,69 @@
+package source
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aquasecurity/avd-generator/config"
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	docs      []Document
+	renderErr error
+	rendered  []Document
+}
+
+func (f *fakeSource) Load(*Context) ([]Document, error) {
+	return f.docs, nil
+}
+
+func (f *fakeSource) Render(_ *Context, docs []Document) error {
+	f.rendered = docs
+	return f.renderErr
+}
+
+func TestRegistry(t *testing.T) {
+	Register("fake", func() Source { return &fakeSource{} })
+	defer delete(registry, "fake")
+
+	s, err := New("fake")
+	require.NoError(t, err)
+	assert.IsType(t, &fakeSource{}, s)
+	assert.Contains(t, Types(), "fake")
+
+	_, err = New("missing")
+	assert.EqualError(t, err, `unknown source type "missing"`)
+
+	assert.Panics(t, func() { Register("fake", func() Source { return &fakeSource{} }) })
+}
+
+func TestRun(t *testing.T) {
+	ctx := &Context{Source: config.Source{Name: "fake"}}
+
+	s := &fakeSource{docs: []Document{{ID: "a"}, {ID: "b"}}}
+	result := Run(ctx, s)
+	require.NoError(t, result.Err)
+	assert.Equal(t, "fake", result.Name)
+	assert.Equal(t, 2, result.Documents)
+	assert.Equal(t, s.docs, s.rendered)
+
+	s = &fakeSource{renderErr: errors.New("disk full")}
+	result = Run(ctx, s)
+	assert.EqualError(t, result.Err, "render: disk full")
+}
+
+func TestRunReport(t *testing.T) {
+	ctx := &Context{Source: config.Source{Name: "fake"}, Report: report.New()}
+
+	ctx.RecordError("a.json", report.StageParse, errors.New("unexpected EOF"))
+	Run(ctx, &fakeSource{renderErr: errors.New("disk full")})
+
+	assert.Equal(t, 1, ctx.Report.Count("fake", report.StageParse))
+	assert.Equal(t, 1, ctx.Report.Count("fake", report.StageRender))
+	require.Len(t, ctx.Report.Sources, 1)
+	assert.Equal(t, 2, ctx.Report.Sources[0].Errors)
+	assert.True(t, ctx.Report.Sources[0].Failed)
+}
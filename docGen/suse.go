@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// suseAdvisory is the part of a SUSE CVRF advisory that lists what it fixes,
+// vuln-list stores it at suse/cvrf/<suse or opensuse>/<year>/<advisory ID>.json.
+type suseAdvisory struct {
+	Tracking struct {
+		ID string
+	}
+	ProductTree struct {
+		Relationships []struct {
+			ProductReference          string
+			RelatesToProductReference string
+		}
+	}
+	Vulnerabilities []struct {
+		CVE         string
+		Description string
+		Threats     []struct {
+			Type     string
+			Severity string
+		}
+		ProductStatuses []struct {
+			Type      string
+			ProductID []string
+		}
+	}
+}
+
+// suseSeverities orders the impacts SUSE rates, lowest first.
+var suseSeverities = []string{"low", "moderate", "important", "critical"}
+
+// suseAdapter reads the CVRF advisories of SUSE and openSUSE.
+type suseAdapter struct {
+	index *vendorIndex
+}
+
+func init() {
+	registerVendorAdapter("suse", newSUSEAdapter)
+}
+
+func newSUSEAdapter(dir string) VendorAdapter {
+	return suseAdapter{index: &vendorIndex{dir: dir, build: buildSUSEIndex}}
+}
+
+func buildSUSEIndex(dir string, skip func(file string, err error)) (map[string][]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string][]string)
+	for _, file := range files {
+		a, err := readSUSEAdvisory(file)
+		if err != nil {
+			skip(file, err)
+			continue
+		}
+		for _, v := range a.Vulnerabilities {
+			if v.CVE != "" && !slices.Contains(index[v.CVE], file) {
+				index[v.CVE] = append(index[v.CVE], file)
+			}
+		}
+	}
+	return index, nil
+}
+
+func readSUSEAdvisory(file string) (suseAdvisory, error) {
+	var a suseAdvisory
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return a, err
+	}
+	if err := json.Unmarshal(b, &a); err != nil {
+		return a, fmt.Errorf("%s: %w", file, err)
+	}
+	return a, nil
+}
+
+// products splits the IDs of the products an advisory fixes, such as
+// "SUSE Linux Enterprise Server 15:openssl-1.1.0i-4.3.1", into the platform
+// and the package it ships.
+func (a suseAdvisory) products() map[string][2]string {
+	products := make(map[string][2]string)
+	for _, r := range a.ProductTree.Relationships {
+		products[r.RelatesToProductReference+":"+r.ProductReference] = [2]string{r.RelatesToProductReference, r.ProductReference}
+	}
+	return products
+}
+
+func (suseAdapter) Name() string { return "suse" }
+
+func (v suseAdapter) Locate(cveID, _ string) ([]string, error) { return v.index.Files(cveID) }
+
+func (v suseAdapter) CVEs(year string) ([]string, error) { return v.index.CVEs(year) }
+
+func (v suseAdapter) Skipped() map[string]error { return v.index.Skipped() }
+
+func (v suseAdapter) Load(cveID, _ string) (VendorInfo, error) {
+	files, err := v.index.Files(cveID)
+	if err != nil {
+		return VendorInfo{}, err
+	}
+	if len(files) == 0 {
+		return VendorInfo{}, os.ErrNotExist
+	}
+
+	info := VendorInfo{Name: v.Name()}
+	var severities []string
+	for _, file := range files {
+		a, err := readSUSEAdvisory(file)
+		if err != nil {
+			return VendorInfo{}, err
+		}
+		products := a.products()
+		for _, vuln := range a.Vulnerabilities {
+			if vuln.CVE != cveID {
+				continue
+			}
+			if info.Description == "" {
+				info.Description = vuln.Description
+			}
+			for _, t := range vuln.Threats {
+				if t.Type == "Impact" {
+					severities = append(severities, t.Severity)
+				}
+			}
+			for _, status := range vuln.ProductStatuses {
+				if status.Type != "Fixed" {
+					continue
+				}
+				for _, id := range status.ProductID {
+					product, ok := products[id]
+					if !ok {
+						platform, nvr, found := strings.Cut(id, ":")
+						if !found {
+							continue
+						}
+						product = [2]string{platform, nvr}
+					}
+					name, version := splitNVR(product[1])
+					as := fixedIn("SUSE", product[0], name, version, a.Tracking.ID)
+					if !slices.Contains(info.AffectedSoftware, as) {
+						info.AffectedSoftware = append(info.AffectedSoftware, as)
+					}
+				}
+			}
+		}
+	}
+	info.Severity = severityOf(severities, suseSeverities)
+	return info, nil
+}
@@ -13,6 +13,8 @@ import (
 	"text/template"
 
 	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/aquasecurity/avd-generator/util"
 	"github.com/aquasecurity/tracee/pkg/rules/regosig"
 )
@@ -83,97 +85,139 @@ func TraceePostToMarkdown(tp TraceePost, outputFile *os.File) error {
 	return nil
 }
 
-func generateTraceePages(rulesDir, postsDir string, clock Clock) {
-	err := os.MkdirAll(postsDir, 0755)
-	if err != nil {
-		log.Fatal("unable to create tracee directory ", err)
-	}
+func init() {
+	source.Register("tracee", func() source.Source { return traceeSource{clock: realClock{}} })
+}
 
-	log.Println("generating tracee pages in: ", postsDir)
+type traceeSource struct {
+	clock Clock
+}
 
-	if err := generateRegoSigPages(rulesDir, postsDir, clock); err != nil {
-		log.Fatal("failed to generate rego sig pages: ", err)
+const (
+	regoSignature = "rego"
+	goSignature   = "go"
+)
+
+func (traceeSource) Load(ctx *source.Context) ([]source.Document, error) {
+	rulesDir := ctx.Input("signatures")
+
+	var docs []source.Document
+	regoFiles, err := getAllFilesOfKind(rulesDir, "rego", "_test")
+	if err != nil {
+		return nil, fmt.Errorf("unable to get rego signature files: %w", err)
+	}
+	for _, file := range regoFiles {
+		if findSubstringsInString(file, []string{"helpers", "example", ".go", "aio", "disabled"}) { // TODO: This should be handled by a filter in GetAllFilesOfKind
+			continue
+		}
+		docs = append(docs, source.Document{ID: file, Path: file, Data: regoSignature})
 	}
 
-	if err := generateGoSigPages(rulesDir, postsDir, clock); err != nil {
-		log.Fatal("failed to generate go sig pages: ", err)
+	files, err := getAllFiles(rulesDir)
+	if err != nil {
+		return nil, err
 	}
+	for _, file := range files {
+		if findSubstringsInString(file, []string{"helpers.go", "example.go", "export.go", "traceerego.go", "aio", "common", "mapper"}) || findSuffixSubstringInString(file, []string{".md", ".rego", "test.go", ".disabled"}) {
+			continue
+		}
+		docs = append(docs, source.Document{ID: file, Path: file, Data: goSignature})
+	}
+	return docs, nil
 }
 
-func generateGoSigPages(rulesDir string, postsDir string, clock Clock) error {
-	var files []string
-	var err error
-	if files, err = getAllFiles(rulesDir); err != nil {
-		return err
+func (s traceeSource) Render(ctx *source.Context, docs []source.Document) error {
+	postsDir := ctx.Output()
+	if err := os.MkdirAll(postsDir, 0755); err != nil {
+		return fmt.Errorf("unable to create tracee directory: %w", err)
 	}
 
-	for _, file := range files {
-		func(file string) {
-			defer func() {
-				if err := recover(); err != nil {
-					log.Printf("failed to process file %s: %v", file, err)
-				}
-			}()
+	log.Println("generating tracee pages in: ", postsDir)
 
-			if findSubstringsInString(file, []string{"helpers.go", "example.go", "export.go", "traceerego.go", "aio", "common", "mapper"}) || findSuffixSubstringInString(file, []string{".md", ".rego", "test.go", ".disabled"}) {
-				return
+	var helpers []byte
+	for _, doc := range docs {
+		switch doc.Data {
+		case regoSignature:
+			if helpers == nil {
+				var err error
+				if helpers, err = ioutil.ReadFile(filepath.Join(ctx.Input("signatures"), "rego", "helpers.rego")); err != nil {
+					return fmt.Errorf("unable to read helpers.rego file: %w", err)
+				}
+			}
+			if err := generateRegoSigPage(doc.Path, helpers, postsDir, s.clock, ctx.Menu); err != nil {
+				ctx.RecordError(doc.Path, report.StageParse, err)
 			}
+		case goSignature:
+			if err := generateGoSigPage(doc.Path, postsDir, s.clock, ctx.Menu); err != nil {
+				ctx.RecordError(doc.Path, report.StageParse, err)
+			}
+		}
+	}
+	return nil
+}
 
-			b, _ := ioutil.ReadFile(file)
-			r := strings.NewReplacer(`"`, ``)
-			rTitle := strings.NewReplacer("/", "-", `"`, "")
+func generateGoSigPage(file string, postsDir string, clock Clock, runTimeSecurityMenu *menu.Menu) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to process file: %v", r)
+		}
+	}()
 
-			log.Printf("Processing Tracee go signature file: %s", file)
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	r := strings.NewReplacer(`"`, ``)
+	rTitle := strings.NewReplacer("/", "-", `"`, "")
 
-			mitreAttack := r.Replace(getRegexMatch(`\"(MITRE ATT&CK)\"\:\s*\"(.*)\"`, string(b)))
-			category := r.Replace(getRegexMatch(`\"(Category)\"\:\s*\"(.*)\"`, string(b)))
-			technique := r.Replace(getRegexMatch(`\"(Technique)\"\:\s*\"(.*)\"`, string(b)))
+	log.Printf("Processing Tracee go signature file: %s", file)
 
-			if mitreAttack == "" {
-				mitreAttack = fmt.Sprintf("%s: %s", strings.Title(strings.ReplaceAll(category, "-", " ")), technique)
-			}
+	mitreAttack := r.Replace(getRegexMatch(`\"(MITRE ATT&CK)\"\:\s*\"(.*)\"`, string(b)))
+	category := r.Replace(getRegexMatch(`\"(Category)\"\:\s*\"(.*)\"`, string(b)))
+	technique := r.Replace(getRegexMatch(`\"(Technique)\"\:\s*\"(.*)\"`, string(b)))
 
-			sig := Signature{
-				ID:          r.Replace(getRegexMatch(`(ID)\:\s*\"(.*?)"`, string(b))),
-				Version:     r.Replace(getRegexMatch(`(Version)\:\s*\"(.*?)\"`, string(b))),
-				Name:        rTitle.Replace(getRegexMatch(`(Name)\:\s*\"(.*?)\"`, string(b))),
-				Description: r.Replace(getRegexMatch(`(Description)\:\s*\"(.*?)\"`, string(b))),
-				Severity:    getSeverityName(getRegexMatch(`\"(Severity)\"\:\s*\d`, string(b))),
-				MitreAttack: mitreAttack,
-				GoCode:      string(b),
-			}
+	if mitreAttack == "" {
+		mitreAttack = fmt.Sprintf("%s: %s", strings.Title(strings.ReplaceAll(category, "-", " ")), technique)
+	}
 
-			topLevelIDName := strings.TrimSpace(strings.Split(sig.MitreAttack, ":")[0])
-			topLevelID := strings.ToLower(strings.ReplaceAll(topLevelIDName, " ", "-"))
-			runTimeSecurityMenu.AddNode(topLevelID, strings.Title(topLevelIDName), postsDir, "", []string{"runtime"}, []menu.BreadCrumb{
-				{Name: "Runtime Security", Url: "/tracee"},
-			}, "runtime", true)
-			parentID := topLevelID
+	sig := Signature{
+		ID:          r.Replace(getRegexMatch(`(ID)\:\s*\"(.*?)"`, string(b))),
+		Version:     r.Replace(getRegexMatch(`(Version)\:\s*\"(.*?)\"`, string(b))),
+		Name:        rTitle.Replace(getRegexMatch(`(Name)\:\s*\"(.*?)\"`, string(b))),
+		Description: r.Replace(getRegexMatch(`(Description)\:\s*\"(.*?)\"`, string(b))),
+		Severity:    getSeverityName(getRegexMatch(`\"(Severity)\"\:\s*\d`, string(b))),
+		MitreAttack: mitreAttack,
+		GoCode:      string(b),
+	}
 
-			outputFilepath := filepath.Join(postsDir, parentID, fmt.Sprintf("%s.md", strings.ReplaceAll(sig.ID, "-", "")))
-			if err := os.MkdirAll(filepath.Dir(outputFilepath), 0755); err != nil {
-				log.Printf("error occurred while creating target directory: %s, %s", filepath.Dir(outputFilepath), err)
-			}
+	topLevelIDName := strings.TrimSpace(strings.Split(sig.MitreAttack, ":")[0])
+	topLevelID := strings.ToLower(strings.ReplaceAll(topLevelIDName, " ", "-"))
+	runTimeSecurityMenu.AddNode(topLevelID, strings.Title(topLevelIDName), postsDir, "", []string{"runtime"}, []menu.BreadCrumb{
+		{Name: "Runtime Security", Url: "/tracee"},
+	}, "runtime", true)
+	parentID := topLevelID
 
-			f, err := os.Create(outputFilepath)
-			if err != nil {
-				log.Printf("unable to create tracee markdown file: %s for sig: %s, skipping...\n", err, sig.ID)
-				return
-			}
+	outputFilepath := filepath.Join(postsDir, parentID, fmt.Sprintf("%s.md", strings.ReplaceAll(sig.ID, "-", "")))
+	if err := os.MkdirAll(filepath.Dir(outputFilepath), 0755); err != nil {
+		return err
+	}
 
-			if err = TraceePostToMarkdown(TraceePost{
-				Title:      util.Nicify(strings.Title(strings.ReplaceAll(sig.Name, "-", " "))),
-				TopLevelID: parentID,
-				ParentID:   parentID,
-				ParentName: strings.Title(topLevelIDName),
-				AliasID:    strings.ToLower(strings.ReplaceAll(sig.ID, "-", "")),
-				Date:       clock.Now("2006-01-02"),
-				Signature:  sig,
-			}, f); err != nil {
-				log.Printf("unable to write tracee signature markdown: %s.md, err: %s", sig.ID, err)
-				return
-			}
-		}(file)
+	f, err := os.Create(outputFilepath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err = TraceePostToMarkdown(TraceePost{
+		Title:      util.Nicify(strings.Title(strings.ReplaceAll(sig.Name, "-", " "))),
+		TopLevelID: parentID,
+		ParentID:   parentID,
+		ParentName: strings.Title(topLevelIDName),
+		AliasID:    strings.ToLower(strings.ReplaceAll(sig.ID, "-", "")),
+		Date:       clock.Now("2006-01-02"),
+		Signature:  sig,
+	}, f); err != nil {
+		return fmt.Errorf("unable to write tracee signature markdown %s.md: %w", sig.ID, err)
 	}
 	return nil
 }
@@ -191,90 +235,69 @@ func getRegexMatch(regex, str string) string {
 	return strings.TrimSpace(parts[1])
 }
 
-func generateRegoSigPages(rulesDir string, postsDir string, clock Clock) error {
-	files, err := getAllFilesOfKind(rulesDir, "rego", "_test")
+func generateRegoSigPage(file string, helpers []byte, postsDir string, clock Clock, runTimeSecurityMenu *menu.Menu) error {
+	log.Printf("Processing Tracee rego signature file: %s", file)
+
+	b, err := ioutil.ReadFile(file)
 	if err != nil {
-		log.Println("unable to get rego signature files: ", err)
 		return err
 	}
 
-	helpers, err := ioutil.ReadFile(filepath.Join(rulesDir, "rego", "helpers.rego"))
+	sig, err := regosig.NewRegoSignature("rego", false, string(b), string(helpers))
 	if err != nil {
-		log.Println("unable to read helpers.rego file: ", err)
-		return err
+		return fmt.Errorf("unable to create new rego signature: %w", err)
 	}
+	m, _ := sig.GetMetadata()
 
-	for _, file := range files {
-		if findSubstringsInString(file, []string{"helpers", "example", ".go", "aio", "disabled"}) { // TODO: This should be handled by a filter in GetAllFilesOfKind
-			continue
-		}
-
-		log.Printf("Processing Tracee rego signature file: %s", file)
-
-		b, err := ioutil.ReadFile(file)
-		if err != nil {
-			log.Printf("unable to read signature file: %s, %s\n", file, err)
-			return err
-		}
-
-		sig, err := regosig.NewRegoSignature("rego", false, string(b), string(helpers))
-		if err != nil {
-			log.Printf("unable to create new rego signature in file %s: %s\n", file, err)
-			return err
-		}
-		m, _ := sig.GetMetadata()
-
-		var severity int64
-		if m.Properties["Severity"] != nil {
-			severity, _ = m.Properties["Severity"].(json.Number).Int64()
-		}
-		var ma string
-		if m.Properties["MITRE ATT&CK"] != nil {
-			ma = m.Properties["MITRE ATT&CK"].(string)
-		}
-
-		topLevelIDName := strings.TrimSpace(strings.Split(ma, ":")[0])
-		topLevelID := strings.ToLower(strings.ReplaceAll(topLevelIDName, " ", "-"))
-		runTimeSecurityMenu.AddNode(topLevelID, strings.Title(topLevelIDName), postsDir, "tracee", []string{"runtime"}, []menu.BreadCrumb{
-			{Name: "Tracee", Url: "/tracee"},
-		}, "tracee", false)
-		parentID := topLevelID
-
-		outputFilepath := filepath.Join(postsDir, parentID, fmt.Sprintf("%s.md", strings.ReplaceAll(m.ID, "-", "")))
-		if err := os.MkdirAll(filepath.Dir(outputFilepath), 0755); err != nil {
-			log.Printf("error occurred while creating target directory: %s, %s", filepath.Dir(outputFilepath), err)
-		}
+	var severity int64
+	if m.Properties["Severity"] != nil {
+		severity, _ = m.Properties["Severity"].(json.Number).Int64()
+	}
+	var ma string
+	if m.Properties["MITRE ATT&CK"] != nil {
+		ma = m.Properties["MITRE ATT&CK"].(string)
+	}
 
-		f, err := os.Create(outputFilepath)
-		if err != nil {
-			log.Printf("unable to create tracee markdown file: %s for sig: %s, skipping...\n", err, m.ID)
-			continue
-		}
+	topLevelIDName := strings.TrimSpace(strings.Split(ma, ":")[0])
+	topLevelID := strings.ToLower(strings.ReplaceAll(topLevelIDName, " ", "-"))
+	runTimeSecurityMenu.AddNode(topLevelID, strings.Title(topLevelIDName), postsDir, "tracee", []string{"runtime"}, []menu.BreadCrumb{
+		{Name: "Tracee", Url: "/tracee"},
+	}, "tracee", false)
+	parentID := topLevelID
 
-		if err = TraceePostToMarkdown(TraceePost{
-			Title:      util.Nicify(strings.Title(m.Name)),
-			ParentID:   parentID,
-			ParentName: strings.Title(topLevelIDName),
-			AliasID:    strings.ToLower(strings.ReplaceAll(m.ID, "-", "")),
-			TopLevelID: parentID,
-			Date:       clock.Now("2006-01-02"),
-			Signature: Signature{
-				ID:          m.ID,
-				Version:     m.Version,
-				Name:        strings.ReplaceAll(m.Name, " ", "-"),
-				Description: m.Description,
-				Severity:    SeverityNames[severity],
-				MitreAttack: ma,
-				RegoPolicy:  string(b),
-			},
-		}, f); err != nil {
-			log.Printf("unable to write tracee signature markdown: %s.md, err: %s", m.ID, err)
-			continue
-		}
+	outputFilepath := filepath.Join(postsDir, parentID, fmt.Sprintf("%s.md", strings.ReplaceAll(m.ID, "-", "")))
+	if err := os.MkdirAll(filepath.Dir(outputFilepath), 0755); err != nil {
+		return err
+	}
 
-		// TODO: Add MITRE classification details
-		// TODO: Add ability to append custom aqua blog post from another markdown
+	f, err := os.Create(outputFilepath)
+	if err != nil {
+		return err
 	}
+	defer f.Close()
+
+	if err = TraceePostToMarkdown(TraceePost{
+		Title:      util.Nicify(strings.Title(m.Name)),
+		ParentID:   parentID,
+		ParentName: strings.Title(topLevelIDName),
+		AliasID:    strings.ToLower(strings.ReplaceAll(m.ID, "-", "")),
+		TopLevelID: parentID,
+		Date:       clock.Now("2006-01-02"),
+		Signature: Signature{
+			ID:          m.ID,
+			Version:     m.Version,
+			Name:        strings.ReplaceAll(m.Name, " ", "-"),
+			Description: m.Description,
+			Severity:    SeverityNames[severity],
+			MitreAttack: ma,
+			RegoPolicy:  string(b),
+		},
+	}, f); err != nil {
+		return fmt.Errorf("unable to write tracee signature markdown %s.md: %w", m.ID, err)
+	}
+
+	// TODO: Add MITRE classification details
+	// TODO: Add ability to append custom aqua blog post from another markdown
 	return nil
 }
 
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/aquasecurity/avd-generator/menu"
+	"github.com/aquasecurity/avd-generator/source"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/stretchr/testify/require"
@@ -19,7 +21,9 @@ func Test_generateTraceePages(t *testing.T) {
 	defer func() {
 		_ = os.RemoveAll(postsDir)
 	}()
-	generateTraceePages("../goldens/tracee-sigs", filepath.Join(postsDir, "tracee"), fakeClock{})
+	ctx := newTestContext(map[string]string{"signatures": "../goldens/tracee-sigs"}, filepath.Join(postsDir, "tracee"), menu.New("runsec", postsDir))
+	result := source.Run(ctx, traceeSource{clock: fakeClock{}})
+	require.NoError(t, result.Err)
 
 	gotFiles, err := getAllFiles(postsDir)
 	require.NoError(t, err)
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ubuntuCVE is the part of the Ubuntu CVE tracker's data on a CVE the pages
+// show, vuln-list stores it at ubuntu/<year>/<CVE ID>.json.
+type ubuntuCVE struct {
+	Description string
+	Priority    string
+	Patches     map[string]map[string]struct {
+		Status string
+		Note   string
+	}
+}
+
+// ubuntuAdapter reads the Ubuntu CVE tracker, which tracks the status of every
+// package in every Ubuntu release rather than the versions it affects.
+type ubuntuAdapter struct {
+	dir string
+}
+
+func init() {
+	registerVendorAdapter("ubuntu", newUbuntuAdapter)
+}
+
+func newUbuntuAdapter(dir string) VendorAdapter {
+	return ubuntuAdapter{dir: dir}
+}
+
+func (ubuntuAdapter) Name() string { return "ubuntu" }
+
+func (v ubuntuAdapter) Locate(cveID, year string) ([]string, error) {
+	return locateByYear(v.dir, cveID, year)
+}
+
+func (v ubuntuAdapter) CVEs(year string) ([]string, error) {
+	return cvesByYear(v.dir, year)
+}
+
+func (v ubuntuAdapter) Load(cveID, year string) (VendorInfo, error) {
+	b, err := os.ReadFile(filepath.Join(v.dir, year, fmt.Sprintf("%s.json", cveID)))
+	if err != nil {
+		return VendorInfo{}, err
+	}
+	var cve ubuntuCVE
+	if err := json.Unmarshal(b, &cve); err != nil {
+		return VendorInfo{}, err
+	}
+
+	info := VendorInfo{
+		Name:        v.Name(),
+		Description: cve.Description,
+		Severity:    cve.Priority,
+	}
+	packages := getAllMapKeys(cve.Patches)
+	sort.Strings(packages)
+	for _, p := range packages {
+		releases := getAllMapKeys(cve.Patches[p])
+		sort.Strings(releases)
+		for _, r := range releases {
+			patch := cve.Patches[p][r]
+			status := strings.ToLower(patch.Status)
+			// DNE is a release that never shipped the package
+			if status == "" || status == "dne" {
+				continue
+			}
+			rs := ReleaseStatus{
+				Package:  p,
+				Release:  r,
+				Status:   status,
+				Priority: cve.Priority,
+			}
+			// the note of a released package is the version that fixed it
+			if status == "released" {
+				rs.FixedVersion = patch.Note
+			} else {
+				rs.Note = patch.Note
+			}
+			info.Releases = append(info.Releases, rs)
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aquasecurity/avd-generator/report"
+	"github.com/aquasecurity/avd-generator/source"
+)
+
+// VendorAdapter reads what a vendor, such as a distribution, knows of CVEs from
+// its own data. Both the CVE pages and the reserved pages are enriched through it.
+type VendorAdapter interface {
+	// Name is the vendor as it appears in config and front matter, such as "redhat".
+	Name() string
+	// Locate returns the files that hold what the vendor knows of a CVE of a
+	// year, which are hashed to tell whether its page has to be rendered again.
+	Locate(cveID, year string) ([]string, error)
+	// CVEs returns the CVEs of a year the vendor knows of.
+	CVEs(year string) ([]string, error)
+	// Load returns what the vendor knows of a CVE of a year, os.ErrNotExist when nothing.
+	Load(cveID, year string) (VendorInfo, error)
+}
+
+// VendorInfo is what a vendor knows of a CVE.
+type VendorInfo struct {
+	Name             string
+	Description      string
+	Severity         string
+	CVSS             CVSS
+	Mitigation       string
+	AffectedSoftware []AffectedSoftware
+	// Releases are set by vendors that track a CVE in each of their releases, such as Ubuntu.
+	Releases []ReleaseStatus
+}
+
+// ReleaseStatus is the status of a package in a release of a vendor.
+type ReleaseStatus struct {
+	Package      string
+	Release      string
+	Status       string
+	FixedVersion string
+	Priority     string
+	Note         string
+}
+
+// Badge is the status as the status shortcode of the theme, which colors it by
+// whether a fix exists.
+func (r ReleaseStatus) Badge() string {
+	return fmt.Sprintf("{{< status %q >}}", r.Status)
+}
+
+// vendorTitles are the names of vendors that capitalizing their config name doesn't spell.
+var vendorTitles = map[string]string{
+	"redhat": "Red Hat",
+	"amazon": "Amazon Linux",
+	"suse":   "SUSE",
+}
+
+// Title is the vendor's name as pages show it.
+func (v VendorInfo) Title() string {
+	if title, ok := vendorTitles[v.Name]; ok {
+		return title
+	}
+	if v.Name == "" {
+		return ""
+	}
+	return strings.ToUpper(v.Name[:1]) + v.Name[1:]
+}
+
+// HasAffectedSoftwareStatus is whether the vendor tracks the status of the affected software.
+func (v VendorInfo) HasAffectedSoftwareStatus() bool {
+	return hasAffectedSoftwareStatus(v.AffectedSoftware)
+}
+
+var vendorAdapters = make(map[string]func(dir string) VendorAdapter)
+
+// registerVendorAdapter makes an adapter available to the vendors option of the
+// nvd source, it is called from the init function of the adapter's file.
+func registerVendorAdapter(name string, newAdapter func(dir string) VendorAdapter) {
+	if _, ok := vendorAdapters[name]; ok {
+		panic(fmt.Sprintf("vendor adapter %q registered twice", name))
+	}
+	vendorAdapters[name] = newAdapter
+}
+
+// newVendorAdapters returns the adapters of the comma separated vendors option,
+// in its order, each reading the input of the same name.
+func newVendorAdapters(ctx *source.Context) ([]VendorAdapter, error) {
+	var adapters []VendorAdapter
+	for _, name := range strings.Split(ctx.Source.Options["vendors"], ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		newAdapter, ok := vendorAdapters[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown vendor %q", name)
+		}
+		dir := ctx.Input(name)
+		if dir == "" {
+			return nil, fmt.Errorf("vendor %q has no input", name)
+		}
+		adapters = append(adapters, newAdapter(dir))
+	}
+	return adapters, nil
+}
+
+// vendorIndex maps CVE IDs to the files of a vendor that mention them, for
+// vendors that store their data by package or by advisory rather than by CVE.
+// It is built on first use. Builds call skip with the files they can't read,
+// which are left out of the index rather than failing it.
+type vendorIndex struct {
+	dir     string
+	build   func(dir string, skip func(file string, err error)) (map[string][]string, error)
+	once    sync.Once
+	files   map[string][]string
+	skipped map[string]error
+	err     error
+}
+
+func (idx *vendorIndex) init() {
+	idx.once.Do(func() {
+		if _, err := os.Stat(idx.dir); err != nil {
+			idx.err = err
+			return
+		}
+		idx.files, idx.err = idx.build(idx.dir, func(file string, err error) {
+			if idx.skipped == nil {
+				idx.skipped = make(map[string]error)
+			}
+			idx.skipped[file] = err
+		})
+		for _, files := range idx.files {
+			sort.Strings(files)
+		}
+	})
+}
+
+// Files returns the files that mention a CVE.
+func (idx *vendorIndex) Files(cveID string) ([]string, error) {
+	idx.init()
+	return idx.files[cveID], idx.err
+}
+
+// CVEs returns the CVEs of a year the files mention.
+func (idx *vendorIndex) CVEs(year string) ([]string, error) {
+	idx.init()
+	var ids []string
+	for id := range idx.files {
+		if strings.HasPrefix(id, fmt.Sprintf("CVE-%s-", year)) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, idx.err
+}
+
+// Skipped returns the files left out of the index, with why.
+func (idx *vendorIndex) Skipped() map[string]error {
+	idx.init()
+	return idx.skipped
+}
+
+// recordSkippedVendorFiles records the files the indexes of adapters left out,
+// once the pages that built them are rendered.
+func recordSkippedVendorFiles(ctx *source.Context, adapters []VendorAdapter) {
+	for _, adapter := range adapters {
+		indexed, ok := adapter.(interface{ Skipped() map[string]error })
+		if !ok {
+			continue
+		}
+		skipped := indexed.Skipped()
+		files := make([]string, 0, len(skipped))
+		for file := range skipped {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			ctx.RecordError(file, report.StageParse, fmt.Errorf("%s: %w", adapter.Name(), skipped[file]))
+		}
+	}
+}
+
+// severityOf returns the highest of severities in order, which lists them lowest first.
+func severityOf(severities []string, order []string) string {
+	highest := -1
+	for _, s := range severities {
+		for i, o := range order {
+			if strings.EqualFold(s, o) && i > highest {
+				highest = i
+			}
+		}
+	}
+	if highest < 0 {
+		return ""
+	}
+	return order[highest]
+}
+
+// fixedIn is the affected software row of a package fixed in a release of a vendor.
+func fixedIn(vendor, release, pkg, version, advisory string) AffectedSoftware {
+	return AffectedSoftware{
+		Name:         pkg,
+		Vendor:       fmt.Sprintf("%s/%s", vendor, release),
+		StartVersion: "*",
+		EndVersion:   version + " (excluding)",
+		Status:       "fixed",
+		Notes:        advisory,
+	}
+}
+
+// splitNVR splits a package's name-version-release into its name and its version-release.
+func splitNVR(nvr string) (string, string) {
+	i := strings.LastIndex(nvr, "-")
+	if i <= 0 {
+		return nvr, ""
+	}
+	j := strings.LastIndex(nvr[:i], "-")
+	if j <= 0 {
+		return nvr[:i], nvr[i+1:]
+	}
+	return nvr[:j], nvr[j+1:]
+}
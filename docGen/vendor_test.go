@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aquasecurity/avd-generator/report"
+)
+
+func TestAlpineVendor(t *testing.T) {
+	info, err := newAlpineAdapter("../goldens/json/alpine").Load("CVE-2020-0002", "2020")
+	require.NoError(t, err)
+	assert.Empty(t, info.Severity)
+	// 3.11 never was vulnerable
+	assert.Equal(t, []AffectedSoftware{
+		{Name: "libavc", Vendor: "Alpine/3.10", StartVersion: "*", EndVersion: "1.0.0-r1 (excluding)", Status: "fixed", Notes: "main"},
+	}, info.AffectedSoftware)
+
+	_, err = newAlpineAdapter("../goldens/json/alpine").Load("CVE-2020-1234", "2020")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestAmazonVendor(t *testing.T) {
+	v := newAmazonAdapter("../goldens/json/amazon")
+	info, err := v.Load("CVE-2020-0002", "2020")
+	require.NoError(t, err)
+	assert.Equal(t, "important", info.Severity)
+	assert.Equal(t, []AffectedSoftware{
+		{Name: "libavc", Vendor: "Amazon Linux/1", StartVersion: "*", EndVersion: "1:0.9.8-5.15.amzn1 (excluding)", Status: "fixed", Notes: "ALAS-2020-1350"},
+		{Name: "libavc", Vendor: "Amazon Linux/2", StartVersion: "*", EndVersion: "1.0.0-2.amzn2 (excluding)", Status: "fixed", Notes: "ALAS2-2020-1401"},
+	}, info.AffectedSoftware)
+
+	files, err := v.Locate("CVE-2020-0001", "2020")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"../goldens/json/amazon/2/ALAS2-2020-1401.json"}, files)
+}
+
+func TestSUSEVendor(t *testing.T) {
+	info, err := newSUSEAdapter("../goldens/json/suse/cvrf").Load("CVE-2020-0002", "2020")
+	require.NoError(t, err)
+	// the critical impact is that of another CVE of the advisory
+	assert.Equal(t, "moderate", info.Severity)
+	assert.Equal(t, []AffectedSoftware{
+		{Name: "libavc1", Vendor: "SUSE/SUSE Linux Enterprise Server 15", StartVersion: "*", EndVersion: "1.0.0-3.6.1 (excluding)", Status: "fixed", Notes: "SUSE-SU-2020:0123-1"},
+		{Name: "libavc-devel", Vendor: "SUSE/SUSE Linux Enterprise Server 15", StartVersion: "*", EndVersion: "1.0.0-3.6.1 (excluding)", Status: "fixed", Notes: "SUSE-SU-2020:0123-1"},
+	}, info.AffectedSoftware)
+}
+
+func TestRedHatVendor(t *testing.T) {
+	info, err := newRedHatAdapter("../goldens/json/redhat").Load("CVE-2020-0002", "2020")
+	require.NoError(t, err)
+	assert.Equal(t, "Moderate", info.Severity)
+	assert.Empty(t, info.Mitigation)
+	// the packages Red Hat found not affected are left out
+	assert.Equal(t, []AffectedSoftware{
+		{Name: "chromium-browser", Vendor: "RedHat/Red Hat Enterprise Linux 6 Supplementary", StartVersion: "*", EndVersion: "80.0.3987.87-1.el6_10 (excluding)", Status: "fixed", Notes: "[RHSA-2020:0514](https://access.redhat.com/errata/RHSA-2020:0514)"},
+	}, info.AffectedSoftware)
+
+	t.Run("will not fix with mitigation", func(t *testing.T) {
+		info, err := newRedHatAdapter("../goldens/json/redhat-wontfix").Load("CVE-2020-0002", "2020")
+		require.NoError(t, err)
+		assert.Equal(t, "Do not open untrusted video files with applications built against libavc.", info.Mitigation)
+		assert.Equal(t, []AffectedSoftware{
+			{Name: "chromium-browser", Vendor: "RedHat/Red Hat Enterprise Linux 6 Supplementary", StartVersion: "*", EndVersion: "80.0.3987.87-1.el6_10 (excluding)", Status: "fixed", Notes: "[RHSA-2020:0514](https://access.redhat.com/errata/RHSA-2020:0514)"},
+			{Name: "openssl098e", Vendor: "RedHat/Red Hat Enterprise Linux 7", StartVersion: "*", EndVersion: "*", Status: "will not fix"},
+		}, info.AffectedSoftware)
+	})
+}
+
+func TestUbuntuVendor(t *testing.T) {
+	info, err := newUbuntuAdapter("../goldens/reserved-no-existing-info/ubuntu").Load("CVE-2020-0569", "2020")
+	require.NoError(t, err)
+	assert.Equal(t, "medium", info.Severity)
+	assert.Empty(t, info.AffectedSoftware)
+	// precise/esm and trusty/esm never shipped qtbase
+	assert.Equal(t, []ReleaseStatus{
+		{Package: "qtbase-opensource-src", Release: "bionic", Status: "released", FixedVersion: "5.9.5+dfsg-0ubuntu2.5", Priority: "medium"},
+		{Package: "qtbase-opensource-src", Release: "devel", Status: "not-affected", Priority: "medium", Note: "5.12.5+dfsg-8"},
+	}, info.Releases)
+	assert.Equal(t, `{{< status "released" >}}`, info.Releases[0].Badge())
+}
+
+func TestVendorAdapterMissingDir(t *testing.T) {
+	for name, newAdapter := range vendorAdapters {
+		_, err := newAdapter(filepath.Join(t.TempDir(), name)).Load("CVE-2020-0002", "2020")
+		assert.True(t, errors.Is(err, os.ErrNotExist), name)
+	}
+}
+
+func TestVendorAdapterBrokenFile(t *testing.T) {
+	copyGolden := func(t *testing.T, golden, dst string) {
+		b, err := os.ReadFile(golden)
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0755))
+		require.NoError(t, os.WriteFile(dst, b, 0600))
+	}
+
+	amazonDir := t.TempDir()
+	copyGolden(t, "../goldens/json/amazon/2/ALAS2-2020-1401.json", filepath.Join(amazonDir, "2", "ALAS2-2020-1401.json"))
+	require.NoError(t, os.WriteFile(filepath.Join(amazonDir, "2", "ALAS2-2020-1402.json"), []byte("{"), 0600))
+
+	suseDir := t.TempDir()
+	copyGolden(t, "../goldens/json/suse/cvrf/suse/2020/SUSE-SU-2020:0123-1.json", filepath.Join(suseDir, "suse", "2020", "SUSE-SU-2020:0123-1.json"))
+	require.NoError(t, os.WriteFile(filepath.Join(suseDir, "suse", "2020", "SUSE-SU-2020:0124-1.json"), []byte("{"), 0600))
+
+	// the broken advisory is left out, the others still enrich their CVEs
+	adapters := []VendorAdapter{newAmazonAdapter(amazonDir), newSUSEAdapter(suseDir)}
+	for _, adapter := range adapters {
+		info, err := adapter.Load("CVE-2020-0002", "2020")
+		require.NoError(t, err, adapter.Name())
+		assert.NotEmpty(t, info.AffectedSoftware, adapter.Name())
+	}
+
+	ctx := newTestContext(nil, t.TempDir(), nil)
+	ctx.Report = report.New()
+	recordSkippedVendorFiles(ctx, adapters)
+	assert.Equal(t, 2, ctx.Report.Count("test", report.StageParse))
+}
+
+func TestNewVendorAdapters(t *testing.T) {
+	ctx := newTestContext(map[string]string{"debian": "../goldens/json/debian", "redhat": "../goldens/json/redhat"}, t.TempDir(), nil)
+	ctx.Source.Options["vendors"] = "debian, redhat"
+	adapters, err := newVendorAdapters(ctx)
+	require.NoError(t, err)
+	require.Len(t, adapters, 2)
+	assert.Equal(t, "debian", adapters[0].Name())
+	assert.Equal(t, "redhat", adapters[1].Name())
+
+	ctx.Source.Options["vendors"] = "debian,gentoo"
+	_, err = newVendorAdapters(ctx)
+	assert.EqualError(t, err, `unknown vendor "gentoo"`)
+
+	ctx.Source.Options["vendors"] = "ubuntu"
+	_, err = newVendorAdapters(ctx)
+	assert.EqualError(t, err, `vendor "ubuntu" has no input`)
+}
+
+func TestGenerateVulnerabilityPageVendors(t *testing.T) {
+	nvdDir := "../goldens/json/nvd"
+	postsDir := t.TempDir()
+	g := &vulnGenerator{ctx: newTestContext(nil, postsDir, nil), cweDir: "../goldens/cwe", postsDir: postsDir, vendors: append(testVendors(),
+		newAlpineAdapter("../goldens/json/alpine"),
+		newAmazonAdapter("../goldens/json/amazon"),
+		newSUSEAdapter("../goldens/json/suse/cvrf"),
+	)}
+
+	require.Equal(t, pageWritten, g.generatePage(filepath.Join(nvdDir, "CVE-2020-0002.json"), postsDir))
+	b, err := os.ReadFile(filepath.Join(postsDir, "CVE-2020-0002.md"))
+	require.NoError(t, err)
+	page := string(b)
+	assert.Contains(t, page, `debian_severity: "N/A"
+alpine_severity: "N/A"
+amazon_severity: "IMPORTANT"
+suse_severity: "MODERATE"
+`)
+	assert.Contains(t, page, `
+| Chromium-browser | RedHat/Red Hat Enterprise Linux 6 Supplementary | * | 80.0.3987.87-1.el6_10 (excluding) | fixed | [RHSA-2020:0514](https://access.redhat.com/errata/RHSA-2020:0514) |
+| Libavc | Alpine/3.10 | * | 1.0.0-r1 (excluding) | fixed | main |
+| Libavc | Amazon Linux/1 | * | 1:0.9.8-5.15.amzn1 (excluding) | fixed | ALAS-2020-1350 |
+| Libavc | Amazon Linux/2 | * | 1.0.0-2.amzn2 (excluding) | fixed | ALAS2-2020-1401 |
+| Libavc1 | SUSE/SUSE Linux Enterprise Server 15 | * | 1.0.0-3.6.1 (excluding) | fixed | SUSE-SU-2020:0123-1 |
+| Libavc-devel | SUSE/SUSE Linux Enterprise Server 15 | * | 1.0.0-3.6.1 (excluding) | fixed | SUSE-SU-2020:0123-1 |
+`)
+}
+
+func TestGenerateVulnerabilityPageRedHatWontFix(t *testing.T) {
+	postsDir := t.TempDir()
+	g := &vulnGenerator{ctx: newTestContext(nil, postsDir, nil), cweDir: "../goldens/cwe", postsDir: postsDir, vendors: []VendorAdapter{
+		newRedHatAdapter("../goldens/json/redhat-wontfix"),
+	}}
+
+	require.Equal(t, pageWritten, g.generatePage(filepath.Join("../goldens/json/nvd", "CVE-2020-0002.json"), postsDir))
+	b, err := os.ReadFile(filepath.Join(postsDir, "CVE-2020-0002.md"))
+	require.NoError(t, err)
+	page := string(b)
+	assert.Contains(t, page, `
+| Chromium-browser | RedHat/Red Hat Enterprise Linux 6 Supplementary | * | 80.0.3987.87-1.el6_10 (excluding) | fixed | [RHSA-2020:0514](https://access.redhat.com/errata/RHSA-2020:0514) |
+| Openssl098e | RedHat/Red Hat Enterprise Linux 7 | * | * | will not fix |  |
+`)
+	assert.Contains(t, page, `
+### Red Hat Mitigation {.with_icon .mitigations}
+Do not open untrusted video files with applications built against libavc.
+`)
+}
+
+func TestSplitNVR(t *testing.T) {
+	testCases := []struct {
+		nvr             string
+		expectedName    string
+		expectedVersion string
+	}{
+		{nvr: "chromium-browser-80.0.3987.87-1.el6_10", expectedName: "chromium-browser", expectedVersion: "80.0.3987.87-1.el6_10"},
+		{nvr: "openssl-1:1.0.2k-19.el7", expectedName: "openssl", expectedVersion: "1:1.0.2k-19.el7"},
+		{nvr: "libavc1-1.0.0-3.6.1", expectedName: "libavc1", expectedVersion: "1.0.0-3.6.1"},
+		{nvr: "kernel-5.14.0", expectedName: "kernel", expectedVersion: "5.14.0"},
+		{nvr: "kernel", expectedName: "kernel"},
+	}
+	for _, tc := range testCases {
+		name, version := splitNVR(tc.nvr)
+		assert.Equal(t, tc.expectedName, name, tc.nvr)
+		assert.Equal(t, tc.expectedVersion, version, tc.nvr)
+	}
+}